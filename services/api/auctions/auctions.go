@@ -2,26 +2,38 @@ package auctions
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"encore.dev/beta/errs"
 	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
 	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/bidding"
+	"seattlereuse.exchange/api/catalog"
 )
 
 // Auction represents a live auction for an item
 type Auction struct {
-	ID                    uuid.UUID `json:"id" db:"id"`
-	ItemID                uuid.UUID `json:"item_id" db:"item_id"`
-	StartsAt              time.Time `json:"starts_at" db:"starts_at"`
-	EndsAt                time.Time `json:"ends_at" db:"ends_at"`
-	ReservePrice          float64   `json:"reserve_price" db:"reserve_price"`
-	MinIncrement          float64   `json:"min_increment" db:"min_increment"`
-	Status                string    `json:"status" db:"status"`
-	AntiSnipingWindowSec  int       `json:"anti_sniping_window_sec" db:"anti_sniping_window_sec"`
-	CurrentBid            *float64  `json:"current_bid,omitempty"`
-	BidCount              int       `json:"bid_count"`
-	TimeRemaining         *string   `json:"time_remaining,omitempty"`
-	ExtensionCount        int       `json:"extension_count"`
+	ID                   uuid.UUID  `json:"id" db:"id"`
+	ItemID               uuid.UUID  `json:"item_id" db:"item_id"`
+	LotID                *uuid.UUID `json:"lot_id,omitempty"` // Set when the item is a lot listing
+	StartsAt             time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt               time.Time  `json:"ends_at" db:"ends_at"`
+	ReservePrice         float64    `json:"reserve_price,omitempty" db:"reserve_price"` // Hidden from bidders
+	MinIncrement         float64    `json:"min_increment" db:"min_increment"`
+	Status               string     `json:"status" db:"status"`
+	AntiSnipingWindowSec int        `json:"anti_sniping_window_sec" db:"anti_sniping_window_sec"`
+	CurrentBid           *float64   `json:"current_bid,omitempty"`
+	BidCount             int        `json:"bid_count"`
+	TimeRemaining        *string    `json:"time_remaining,omitempty"`
+	ExtensionCount       int        `json:"extension_count"`
+	WatcherCount         int        `json:"watcher_count" db:"watcher_count"` // Users following the auction
+	Outcome              string     `json:"outcome,omitempty" db:"outcome"`   // Set once closed, see AuctionOutcome
+	ItemTitle            string     `json:"item_title,omitempty"`             // Set in listings
+	RelistedFrom         *uuid.UUID `json:"relisted_from,omitempty"`          // The unsold or cancelled auction this one relists
 }
 
 // AuctionStatus defines auction states
@@ -33,6 +45,7 @@ const (
 	StatusOpen      AuctionStatus = "open"
 	StatusClosed    AuctionStatus = "closed"
 	StatusSettled   AuctionStatus = "settled"
+	StatusCancelled AuctionStatus = "cancelled"
 )
 
 var db = sqldb.Named("seattle_reuse")
@@ -45,10 +58,28 @@ func CreateAuction(ctx context.Context, req *CreateAuctionRequest) (*Auction, er
 	// - Reserve price suggestions using market data
 	// - Best start times for maximum visibility
 	// - Anti-sniping window recommendations
+	// New auctions start as drafts; scheduling them commits to the dates.
+
+	// A lot is auctioned through the item that lists it
+	itemID := req.ItemID
+	if req.LotID != nil {
+		lot, err := catalog.GetLot(ctx, req.LotID.String())
+		if err != nil {
+			return nil, err
+		}
+		if itemID != uuid.Nil && itemID != lot.ItemID {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("item_id does not match the lot's item").Err()
+		}
+		itemID = lot.ItemID
+	}
+	if itemID == uuid.Nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("item_id or lot_id is required").Err()
+	}
 
 	auction := &Auction{
 		ID:                   uuid.New(),
-		ItemID:               req.ItemID,
+		ItemID:               itemID,
+		LotID:                req.LotID,
 		StartsAt:             req.StartsAt,
 		EndsAt:               req.EndsAt,
 		ReservePrice:         req.ReservePrice,
@@ -57,29 +88,86 @@ func CreateAuction(ctx context.Context, req *CreateAuctionRequest) (*Auction, er
 		AntiSnipingWindowSec: 120, // 2 minutes default
 		ExtensionCount:       0,
 	}
+	if err := validateAuction(auction); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertAuction(ctx, tx, auction, nil, ""); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
 
-	// TODO: Insert into database
-	// TODO: Schedule auction start/end jobs
 	// TODO: Notify subscribers
 
 	return auction, nil
 }
 
+//encore:api public method=POST path=/v1/auctions/:id/schedule
+func ScheduleAuction(ctx context.Context, id string) (*Auction, error) {
+	// AI-CHAT: Publishes a draft auction so it opens at its start time
+	// The auction scheduler opens and closes scheduled auctions on time.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auction.EndsAt.After(time.Now()) {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("the auction's end time has already passed").Err()
+	}
+	if err := checkItemAuctionable(ctx, tx, auction.ItemID); err != nil {
+		return nil, err
+	}
+	if err := transitionAuction(ctx, tx, auction, StatusScheduled, nil, ""); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
+	return auction, nil
+}
+
 //encore:api public method=POST path=/v1/auctions/:id/open
 func OpenAuction(ctx context.Context, id string) (*Auction, error) {
 	// AI-CHAT: Opens auction for bidding
 	// Triggers notifications to interested users
 	// Starts real-time bid tracking
 	// Begins anti-sniping monitoring
+	// Opening before the scheduled start moves the start to now.
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := openLockedAuction(ctx, tx, auction); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
 
-	// TODO: Update auction status to "open"
 	// TODO: Send opening notifications
 	// TODO: Start real-time event stream
 
-	return &Auction{
-		ID:     uuid.MustParse(id),
-		Status: string(StatusOpen),
-	}, nil
+	return auction, nil
 }
 
 //encore:api public method=POST path=/v1/auctions/:id/close
@@ -88,50 +176,130 @@ func CloseAuction(ctx context.Context, id string) (*Auction, error) {
 	// Handles winner notification and payment processing
 	// Manages fallback to next highest bidder if needed
 	// Updates inventory status
+	// Auctions close once their end time has passed; to stop one early,
+	// cancel it instead. The highest bid placed in time wins if it meets the
+	// reserve, and the winner gets a pending order. Winner emails and other
+	// follow-ups subscribe to AuctionClosed.
 
-	// TODO: Update auction status to "closed"
-	// TODO: Determine winner and send notifications
-	// TODO: Create order for winner
-	// TODO: Handle reserve price logic
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	return &Auction{
-		ID:     uuid.MustParse(id),
-		Status: string(StatusClosed),
-	}, nil
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	event, err := closeLockedAuction(ctx, tx, auction)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
+	publishClosed(ctx, event)
+
+	return auction, nil
+}
+
+//encore:api public method=POST path=/v1/auctions/:id/settle
+func SettleAuction(ctx context.Context, id string, req *SettleAuctionRequest) (*Auction, error) {
+	// AI-CHAT: Marks a sold auction as settled once the winner has paid,
+	// completing its lifecycle. Staff settle auctions after confirming the
+	// payment.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	var orderStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE((
+			SELECT status FROM orders WHERE auction_id = $1 AND status IN ('pending', 'paid')
+		), '')
+	`, auction.ID).Scan(&orderStatus)
+	if err != nil {
+		return nil, fmt.Errorf("load auction order: %w", err)
+	}
+	if problem := settleProblem(AuctionOutcome(auction.Outcome), orderStatus); problem != "" {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg(problem).Err()
+	}
+	if err := transitionAuction(ctx, tx, auction, StatusSettled, req.SettledBy, ""); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
+	return auction, nil
 }
 
 //encore:api public method=GET path=/v1/auctions/:id
-func GetAuction(ctx context.Context, id string) (*AuctionDetailResponse, error) {
+func GetAuction(ctx context.Context, id string, req *GetAuctionRequest) (*AuctionDetailResponse, error) {
 	// AI-CHAT: Returns detailed auction information
 	// Includes real-time bid updates and countdown
 	// Shows bid history and user engagement metrics
 	// Provides AI-powered bidding insights and strategy tips
+	// Bidders are anonymized and the reserve amount is never shown, only
+	// whether it has been met. Signed-in viewers also see their own standing
+	// and which bids are theirs.
 
-	auction := &Auction{
-		ID:                   uuid.MustParse(id),
-		ItemID:               uuid.New(),
-		StartsAt:             time.Now().Add(-1 * time.Hour),
-		EndsAt:               time.Now().Add(2 * time.Hour),
-		ReservePrice:         100.0,
-		MinIncrement:         5.0,
-		Status:               string(StatusOpen),
-		AntiSnipingWindowSec: 120,
-		CurrentBid:           ptr(125.0),
-		BidCount:             8,
-		ExtensionCount:       0,
+	auctionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid auction id").Err()
+	}
+	auction, err := scanAuction(db.QueryRow(ctx, "SELECT "+auctionColumns+auctionFrom+" WHERE a.id = $1", auctionID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("auction %s not found", auctionID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load auction: %w", err)
+	}
+	err = db.QueryRow(ctx, `
+		SELECT MAX(amount), COUNT(*) FROM bids WHERE auction_id = $1 AND voided_at IS NULL
+	`, auction.ID).Scan(&auction.CurrentBid, &auction.BidCount)
+	if err != nil {
+		return nil, fmt.Errorf("load bid summary: %w", err)
 	}
 
-	// Calculate time remaining
-	timeRemaining := auction.EndsAt.Sub(time.Now())
-	if timeRemaining > 0 {
-		timeRemainingStr := formatDuration(timeRemaining)
-		auction.TimeRemaining = &timeRemainingStr
+	setTimeRemaining(auction, time.Now())
+
+	item, err := catalog.GetItem(ctx, auction.ItemID.String(), &catalog.GetItemRequest{})
+	if err != nil {
+		return nil, err
+	}
+	limit := req.BidLimit
+	if limit < 1 {
+		limit = defaultRecentBids
+	} else if limit > maxRecentBids {
+		limit = maxRecentBids
+	}
+	viewerID := authenticatedViewer()
+	recent, err := loadRecentBids(ctx, auction.ID, viewerID, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	return &AuctionDetailResponse{
-		Auction: auction,
-		// TODO: Include item details, bid history, etc.
-	}, nil
+	resp := &AuctionDetailResponse{
+		Auction:    auction,
+		Item:       item,
+		RecentBids: recent,
+		NextMinBid: bidding.NextMinimumBid(auction.CurrentBid, auction.MinIncrement),
+		HasReserve: auction.ReservePrice > 0,
+		ReserveMet: auction.ReservePrice == 0 ||
+			(auction.CurrentBid != nil && *auction.CurrentBid >= auction.ReservePrice),
+	}
+	if viewerID != uuid.Nil {
+		if resp.ViewerBid, err = loadViewerBidStatus(ctx, auction, viewerID); err != nil {
+			return nil, err
+		}
+	}
+	auction.ReservePrice = 0
+	return resp, nil
 }
 
 //encore:api public method=GET path=/v1/auctions
@@ -140,27 +308,146 @@ func GetAuctions(ctx context.Context, req *GetAuctionsRequest) (*GetAuctionsResp
 	// Supports filtering by status, category, ending soon, etc.
 	// Includes AI-powered recommendations based on user history
 	// Shows personalized auction relevance scores
+	// Sorts by ends_at (soonest first, the default), current_bid, bid_count
+	// or newest.
+
+	if req.Status != "" && !isValidStatus(req.Status) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid status").Err()
+	}
+	sort := req.Sort
+	if sort == "" {
+		sort = "ends_at"
+	}
+	orderBy, ok := auctionSorts[sort]
+	if !ok {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("sort must be one of ends_at, current_bid, bid_count, newest").Err()
+	}
+	if req.EndingWithinMinutes < 0 {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("ending_within_minutes must not be negative").Err()
+	}
+
+	now := time.Now()
+	page, limit := normalizePage(req.Page, req.Limit)
+	where, args := buildAuctionFilters(req, now)
+
+	var total int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*)"+auctionListFrom+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count auctions: %w", err)
+	}
+
+	query := "SELECT " + auctionColumns + ", i.title, b.current_bid, b.bid_count" + auctionListFrom + where +
+		orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := db.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("query auctions: %w", err)
+	}
+	defer rows.Close()
 
-	auctions := []*Auction{
-		{
-			ID:           uuid.New(),
-			ItemID:       uuid.New(),
-			StartsAt:     time.Now().Add(-2 * time.Hour),
-			EndsAt:       time.Now().Add(1 * time.Hour),
-			ReservePrice: 150.0,
-			CurrentBid:   ptr(175.0),
-			BidCount:     12,
-			Status:       string(StatusOpen),
-		},
+	auctions := []*Auction{}
+	for rows.Next() {
+		var (
+			title      string
+			currentBid *float64
+			bidCount   int
+		)
+		auction, err := scanAuction(rows, &title, &currentBid, &bidCount)
+		if err != nil {
+			return nil, fmt.Errorf("scan auction: %w", err)
+		}
+		auction.ItemTitle = title
+		auction.CurrentBid = currentBid
+		auction.BidCount = bidCount
+		auction.ReservePrice = 0 // Bidders never see the reserve
+		setTimeRemaining(auction, now)
+		auctions = append(auctions, auction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate auctions: %w", err)
 	}
 
 	return &GetAuctionsResponse{
 		Auctions: auctions,
-		Total:    len(auctions),
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
 	}, nil
 }
 
 // Helper functions
+
+// validateAuction checks the fields of a new auction.
+func validateAuction(a *Auction) error {
+	if problem := auctionProblem(a); problem != "" {
+		return errs.B().Code(errs.InvalidArgument).Msg(problem).Err()
+	}
+	return nil
+}
+
+// auctionProblem describes what is wrong with a new auction's fields, or
+// returns "" if they are valid.
+func auctionProblem(a *Auction) string {
+	switch {
+	case a.StartsAt.IsZero() || a.EndsAt.IsZero():
+		return "starts_at and ends_at are required"
+	case !a.EndsAt.After(a.StartsAt):
+		return "ends_at must be after starts_at"
+	case a.ReservePrice < 0:
+		return "reserve_price must not be negative"
+	case a.MinIncrement < 0:
+		return "min_increment must not be negative"
+	}
+	return ""
+}
+
+// insertAuction checks a new draft auction's item can be auctioned, saves
+// the auction and records its creation.
+func insertAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction, actorID *uuid.UUID, reason string) error {
+	if err := checkItemAuctionable(ctx, tx, auction.ItemID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO auctions (id, item_id, starts_at, ends_at, reserve_price, min_increment,
+			status, anti_sniping_window_sec, relisted_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, auction.ID, auction.ItemID, auction.StartsAt, auction.EndsAt, auction.ReservePrice,
+		auction.MinIncrement, auction.Status, auction.AntiSnipingWindowSec, auction.RelistedFrom)
+	if sqldb.ErrCode(err) == sqlerr.UniqueViolation {
+		return errs.B().Code(errs.AlreadyExists).Msg("the item already has an auction running").Err()
+	} else if err != nil {
+		return fmt.Errorf("insert auction: %w", err)
+	}
+	return recordAuctionEvent(ctx, tx, auction.ID, "auction.created", actorID, &transitionMeta{To: StatusDraft, Reason: reason})
+}
+
+// checkItemAuctionable locks an item and checks it can be put up for
+// auction: listed, not deleted, not flagged for review and not grouped into
+// a lot (lots are auctioned through their listing item).
+func checkItemAuctionable(ctx context.Context, tx *sqldb.Tx, itemID uuid.UUID) error {
+	var (
+		status  string
+		flagged bool
+		inLot   bool
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT status, flagged_for_review, lot_id IS NOT NULL
+		FROM items WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, itemID).Scan(&status, &flagged, &inLot)
+	switch {
+	case errors.Is(err, sqldb.ErrNoRows):
+		return errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	case err != nil:
+		return fmt.Errorf("load item: %w", err)
+	case inLot:
+		return errs.B().Code(errs.FailedPrecondition).Msg("items in a lot are auctioned with the lot").Err()
+	case flagged:
+		return errs.B().Code(errs.FailedPrecondition).Msg("the item is flagged for prohibited items review").Err()
+	case status != string(catalog.StatusListed):
+		return errs.B().Code(errs.FailedPrecondition).Msgf("only listed items can be auctioned, the item is %s", status).Err()
+	}
+	return nil
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Hour {
 		return d.Round(time.Minute).String()
@@ -174,27 +461,46 @@ func ptr[T any](v T) *T {
 
 // Request/Response types
 type CreateAuctionRequest struct {
-	ItemID       uuid.UUID `json:"item_id"`
-	StartsAt     time.Time `json:"starts_at"`
-	EndsAt       time.Time `json:"ends_at"`
-	ReservePrice float64   `json:"reserve_price"`
-	MinIncrement float64   `json:"min_increment"`
+	ItemID       uuid.UUID  `json:"item_id"`
+	LotID        *uuid.UUID `json:"lot_id,omitempty"` // Auction a whole lot instead of a single item
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	ReservePrice float64    `json:"reserve_price"`
+	MinIncrement float64    `json:"min_increment"`
+}
+
+type SettleAuctionRequest struct {
+	SettledBy *uuid.UUID `json:"settled_by,omitempty"` // Staff member confirming the payment
+}
+
+type GetAuctionRequest struct {
+	BidLimit int `query:"bid_limit"` // Recent bids to include, 10 by default
 }
 
 type AuctionDetailResponse struct {
-	Auction *Auction `json:"auction"`
-	// TODO: Add item details, bid history, etc.
+	Auction    *Auction         `json:"auction"` // Without the reserve price
+	Item       *catalog.Item    `json:"item"`
+	RecentBids []*BidSummary    `json:"recent_bids"` // Newest first
+	NextMinBid float64          `json:"next_min_bid"`
+	HasReserve bool             `json:"has_reserve"`
+	ReserveMet bool             `json:"reserve_met"`
+	ViewerBid  *ViewerBidStatus `json:"viewer_bid,omitempty"` // Signed-in bidders only
 }
 
 type GetAuctionsRequest struct {
 	Status     string `query:"status"`
 	Category   string `query:"category"`
 	EndingSoon bool   `query:"ending_soon"`
-	Page       int    `query:"page"`
-	Limit      int    `query:"limit"`
+	// EndingWithinMinutes is the ending-soon window, an hour by default
+	EndingWithinMinutes int    `query:"ending_within_minutes"`
+	Sort                string `query:"sort"`
+	Page                int    `query:"page"`
+	Limit               int    `query:"limit"`
 }
 
 type GetAuctionsResponse struct {
 	Auctions []*Auction `json:"auctions"`
 	Total    int        `json:"total"`
-}
\ No newline at end of file
+	Page     int        `json:"page"`
+	Limit    int        `json:"limit"`
+}
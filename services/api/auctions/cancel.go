@@ -0,0 +1,217 @@
+package auctions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// AuctionCancelledEvent is published once an auction is cancelled through
+// CancelAuction, so everyone who bid can be told why.
+type AuctionCancelledEvent struct {
+	AuctionID   uuid.UUID   `json:"auction_id"`
+	ItemID      uuid.UUID   `json:"item_id"`
+	Reason      string      `json:"reason"`
+	BidderIDs   []uuid.UUID `json:"bidder_ids"`
+	CancelledAt time.Time   `json:"cancelled_at"`
+}
+
+// AuctionCancelled is where cancelled auctions are announced.
+var AuctionCancelled = pubsub.NewTopic[*AuctionCancelledEvent]("auction-cancelled", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// relistableOutcomes are the outcomes of a closed auction whose item went
+// unsold and may be relisted.
+var relistableOutcomes = map[AuctionOutcome]bool{
+	OutcomeReserveNotMet: true,
+	OutcomeNoBids:        true,
+	OutcomeUnpaid:        true,
+}
+
+//encore:api public method=POST path=/v1/auctions/:id/cancel
+func CancelAuction(ctx context.Context, id string, req *CancelAuctionRequest) (*Auction, error) {
+	// AI-CHAT: Stops an auction that hasn't finished, e.g. because the item
+	// was damaged or withdrawn. Bids are voided, the item goes back to
+	// listed and every bidder is told why. Closed auctions can't be cancelled.
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("a reason is required to cancel an auction").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if problem := cancelProblem(auction, time.Now()); problem != "" {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg(problem).Err()
+	}
+	wasOpen := auction.Status == string(StatusOpen)
+	if err := transitionAuction(ctx, tx, auction, StatusCancelled, req.CancelledBy, reason); err != nil {
+		return nil, err
+	}
+	if wasOpen {
+		if err := releaseItem(ctx, tx, auction.ItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	event := &AuctionCancelledEvent{
+		AuctionID:   auction.ID,
+		ItemID:      auction.ItemID,
+		Reason:      reason,
+		BidderIDs:   []uuid.UUID{},
+		CancelledAt: time.Now(),
+	}
+	voided, err := voidBids(ctx, tx, auction.ID, event)
+	if err != nil {
+		return nil, err
+	}
+	if voided > 0 {
+		meta := map[string]interface{}{"bids": voided, "bidders": len(event.BidderIDs)}
+		if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.bids_voided", req.CancelledBy, meta); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
+
+	if _, err := AuctionCancelled.Publish(ctx, event); err != nil {
+		rlog.Error("failed to publish auction cancelled event", "auction_id", auction.ID, "err", err)
+	}
+	return auction, nil
+}
+
+//encore:api public method=POST path=/v1/auctions/:id/relist
+func RelistAuction(ctx context.Context, id string, req *RelistAuctionRequest) (*Auction, error) {
+	// AI-CHAT: Puts the item of a cancelled or unsold auction up again with
+	// new dates. The new auction copies the old one's settings and starts as
+	// a draft; the reserve can only be kept or lowered.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	source, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	auction, code, msg := relistedAuction(source, req)
+	if code != errs.OK {
+		return nil, errs.B().Code(code).Msg(msg).Err()
+	}
+
+	if err := insertAuction(ctx, tx, auction, req.RelistedBy, "relisted from "+source.ID.String()); err != nil {
+		return nil, err
+	}
+	meta := map[string]interface{}{"auction_id": auction.ID, "reserve_price": auction.ReservePrice}
+	if err := recordAuctionEvent(ctx, tx, source.ID, "auction.relisted", req.RelistedBy, meta); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit auction: %w", err)
+	}
+	return auction, nil
+}
+
+// cancelProblem explains why an auction can't be cancelled now, or returns
+// "" if it can. Once an open auction's end time has passed its bidding is
+// over, even if the scheduler hasn't closed it yet, and its winner must not
+// lose the item. The lifecycle rules are checked by transitionAuction.
+func cancelProblem(auction *Auction, now time.Time) string {
+	if auction.Status == string(StatusOpen) && !auction.EndsAt.After(now) {
+		return "bidding has ended; the auction is about to close"
+	}
+	return ""
+}
+
+// relistedAuction builds the draft auction that relists source with the dates
+// and reserve in req. It returns errs.OK, or the error code and message to
+// reject the relist with: source must be cancelled or closed unsold, the
+// reserve may only be kept or lowered and the new dates must be valid.
+func relistedAuction(source *Auction, req *RelistAuctionRequest) (*Auction, errs.ErrCode, string) {
+	unsold := source.Status == string(StatusClosed) && relistableOutcomes[AuctionOutcome(source.Outcome)]
+	if source.Status != string(StatusCancelled) && !unsold {
+		return nil, errs.FailedPrecondition, "only cancelled or unsold auctions can be relisted"
+	}
+
+	auction := &Auction{
+		ID:                   uuid.New(),
+		ItemID:               source.ItemID,
+		LotID:                source.LotID,
+		StartsAt:             req.StartsAt,
+		EndsAt:               req.EndsAt,
+		ReservePrice:         source.ReservePrice,
+		MinIncrement:         source.MinIncrement,
+		Status:               string(StatusDraft),
+		AntiSnipingWindowSec: source.AntiSnipingWindowSec,
+		RelistedFrom:         &source.ID,
+	}
+	if req.ReservePrice != nil {
+		if *req.ReservePrice > source.ReservePrice {
+			return nil, errs.InvalidArgument, "the reserve price can only be lowered when relisting"
+		}
+		auction.ReservePrice = *req.ReservePrice
+	}
+	if problem := auctionProblem(auction); problem != "" {
+		return nil, errs.InvalidArgument, problem
+	}
+	return auction, errs.OK, ""
+}
+
+// voidBids voids the bids on a cancelled auction, collecting the bidders
+// into event, and returns how many bids were voided.
+func voidBids(ctx context.Context, tx *sqldb.Tx, auctionID uuid.UUID, event *AuctionCancelledEvent) (int, error) {
+	result, err := tx.Exec(ctx, `
+		UPDATE bids SET voided_at = NOW() WHERE auction_id = $1 AND voided_at IS NULL
+	`, auctionID)
+	if err != nil {
+		return 0, fmt.Errorf("void bids: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT user_id FROM bids WHERE auction_id = $1 AND user_id IS NOT NULL
+	`, auctionID)
+	if err != nil {
+		return 0, fmt.Errorf("query bidders: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return 0, fmt.Errorf("scan bidder: %w", err)
+		}
+		event.BidderIDs = append(event.BidderIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate bidders: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+type CancelAuctionRequest struct {
+	Reason      string     `json:"reason"`
+	CancelledBy *uuid.UUID `json:"cancelled_by,omitempty"` // Staff member cancelling the auction
+}
+
+type RelistAuctionRequest struct {
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	ReservePrice *float64   `json:"reserve_price,omitempty"` // Defaults to the old reserve
+	RelistedBy   *uuid.UUID `json:"relisted_by,omitempty"`
+}
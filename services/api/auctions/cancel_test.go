@@ -0,0 +1,100 @@
+package auctions
+
+import (
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/google/uuid"
+)
+
+func TestRelistedAuction(t *testing.T) {
+	start := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	dates := &RelistAuctionRequest{StartsAt: start, EndsAt: start.Add(72 * time.Hour)}
+	withReserve := func(reserve float64) *RelistAuctionRequest {
+		req := *dates
+		req.ReservePrice = &reserve
+		return &req
+	}
+
+	testCases := []struct {
+		name     string
+		status   AuctionStatus
+		outcome  AuctionOutcome
+		req      *RelistAuctionRequest
+		expected errs.ErrCode
+		reserve  float64
+	}{
+		{"cancelled", StatusCancelled, "", dates, errs.OK, 100},
+		{"reserve not met", StatusClosed, OutcomeReserveNotMet, dates, errs.OK, 100},
+		{"no bids", StatusClosed, OutcomeNoBids, dates, errs.OK, 100},
+		{"unpaid", StatusClosed, OutcomeUnpaid, dates, errs.OK, 100},
+		{"sold", StatusClosed, OutcomeSold, dates, errs.FailedPrecondition, 0},
+		{"settled", StatusSettled, OutcomeSold, dates, errs.FailedPrecondition, 0},
+		{"still open", StatusOpen, "", dates, errs.FailedPrecondition, 0},
+		{"draft", StatusDraft, "", dates, errs.FailedPrecondition, 0},
+		{"reserve lowered", StatusCancelled, "", withReserve(60), errs.OK, 60},
+		{"reserve kept", StatusCancelled, "", withReserve(100), errs.OK, 100},
+		{"reserve removed", StatusCancelled, "", withReserve(0), errs.OK, 0},
+		{"reserve raised", StatusCancelled, "", withReserve(120), errs.InvalidArgument, 0},
+		{"negative reserve", StatusCancelled, "", withReserve(-1), errs.InvalidArgument, 0},
+		{"no dates", StatusCancelled, "", &RelistAuctionRequest{}, errs.InvalidArgument, 0},
+		{"ends before it starts", StatusCancelled, "", &RelistAuctionRequest{StartsAt: start, EndsAt: start.Add(-time.Hour)}, errs.InvalidArgument, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &Auction{
+				ID:                   uuid.New(),
+				ItemID:               uuid.New(),
+				Status:               string(tc.status),
+				Outcome:              string(tc.outcome),
+				ReservePrice:         100,
+				MinIncrement:         5,
+				AntiSnipingWindowSec: 120,
+			}
+			auction, code, msg := relistedAuction(source, tc.req)
+			if code != tc.expected {
+				t.Fatalf("relistedAuction() = %s %q, expected %s", code, msg, tc.expected)
+			}
+			if code != errs.OK {
+				return
+			}
+			if auction.Status != string(StatusDraft) || auction.ItemID != source.ItemID || auction.RelistedFrom == nil || *auction.RelistedFrom != source.ID {
+				t.Errorf("Expected a draft of the same item relisted from the source, got %+v", auction)
+			}
+			if auction.ReservePrice != tc.reserve {
+				t.Errorf("ReservePrice = %v, expected %v", auction.ReservePrice, tc.reserve)
+			}
+			if auction.MinIncrement != source.MinIncrement || auction.AntiSnipingWindowSec != source.AntiSnipingWindowSec {
+				t.Errorf("Expected the source's bidding settings to be copied, got %+v", auction)
+			}
+		})
+	}
+}
+
+func TestCancelProblem(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		status  AuctionStatus
+		endsAt  time.Time
+		cancels bool
+	}{
+		{"open", StatusOpen, now.Add(time.Hour), true},
+		{"ended, not yet closed", StatusOpen, now.Add(-30 * time.Second), false},
+		{"ends now", StatusOpen, now, false},
+		{"scheduled", StatusScheduled, now.Add(time.Hour), true},
+		{"draft past its dates", StatusDraft, now.Add(-time.Hour), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: string(tc.status), EndsAt: tc.endsAt}
+			if got := cancelProblem(auction, now); (got == "") != tc.cancels {
+				t.Errorf("cancelProblem() = %q, expected cancels=%v", got, tc.cancels)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package auctions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// AuctionOutcome records how a closed auction ended
+type AuctionOutcome string
+
+const (
+	OutcomeSold          AuctionOutcome = "sold"
+	OutcomeReserveNotMet AuctionOutcome = "reserve_not_met"
+	OutcomeNoBids        AuctionOutcome = "no_bids"
+	OutcomeSecondChance  AuctionOutcome = "second_chance" // The winner didn't pay; runner-ups are being offered the item
+	OutcomeUnpaid        AuctionOutcome = "unpaid"        // Neither the winner nor any runner-up paid
+)
+
+// AuctionClosedEvent is published once an auction closes. The winner fields
+// and OrderID are only set when the outcome is OutcomeSold.
+type AuctionClosedEvent struct {
+	AuctionID  uuid.UUID      `json:"auction_id"`
+	ItemID     uuid.UUID      `json:"item_id"`
+	Outcome    AuctionOutcome `json:"outcome"`
+	BidCount   int            `json:"bid_count"`
+	HighestBid *float64       `json:"highest_bid,omitempty"`
+	WinnerID   *uuid.UUID     `json:"winner_id,omitempty"`
+	OrderID    *uuid.UUID     `json:"order_id,omitempty"`
+	ClosedAt   time.Time      `json:"closed_at"`
+}
+
+// AuctionClosed is where closed auctions are announced, for winner emails,
+// notifications and reporting.
+var AuctionClosed = pubsub.NewTopic[*AuctionClosedEvent]("auction-closed", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// closingBid is a bid considered when an auction closes.
+type closingBid struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// settleClose determines the winner of an auction that has just closed,
+// creates the winner's pending order and updates the item's stock. It
+// returns the event to publish once the transaction commits.
+func settleClose(ctx context.Context, tx *sqldb.Tx, auction *Auction) (*AuctionClosedEvent, error) {
+	bids, err := loadClosingBids(ctx, tx, auction)
+	if err != nil {
+		return nil, err
+	}
+	winner, outcome := pickWinner(bids, auction.ReservePrice)
+
+	event := &AuctionClosedEvent{
+		AuctionID: auction.ID,
+		ItemID:    auction.ItemID,
+		Outcome:   outcome,
+		BidCount:  len(bids),
+	}
+	if highest, _ := pickWinner(bids, 0); highest != nil {
+		event.HighestBid = &highest.Amount
+	}
+
+	var winningBidID *uuid.UUID
+	if winner != nil {
+		winningBidID = &winner.ID
+		event.WinnerID = &winner.UserID
+		if err := takeItemStock(ctx, tx, auction.ItemID); err != nil {
+			return nil, err
+		}
+		orderID, err := insertAuctionOrder(ctx, tx, auction, winner.UserID, winner.Amount)
+		if err != nil {
+			return nil, err
+		}
+		event.OrderID = &orderID
+	} else if err := releaseItem(ctx, tx, auction.ItemID); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE auctions SET closed_at = NOW(), outcome = $2, winning_bid_id = $3
+		WHERE id = $1
+		RETURNING closed_at
+	`, auction.ID, string(outcome), winningBidID).Scan(&event.ClosedAt)
+	if err != nil {
+		return nil, fmt.Errorf("record auction outcome: %w", err)
+	}
+	auction.Outcome = string(outcome)
+	return event, nil
+}
+
+// loadClosingBids returns the bids placed before the auction ended.
+func loadClosingBids(ctx context.Context, tx *sqldb.Tx, auction *Auction) ([]*closingBid, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, user_id, amount, created_at FROM bids
+		WHERE auction_id = $1 AND created_at <= $2 AND user_id IS NOT NULL
+	`, auction.ID, auction.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("query bids: %w", err)
+	}
+	defer rows.Close()
+
+	var bids []*closingBid
+	for rows.Next() {
+		b := &closingBid{}
+		if err := rows.Scan(&b.ID, &b.UserID, &b.Amount, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan bid: %w", err)
+		}
+		bids = append(bids, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate bids: %w", err)
+	}
+	return bids, nil
+}
+
+// pickWinner returns the highest bid, the earliest one winning a tie, and the
+// outcome given the reserve price. There is no winner unless the reserve is
+// met; a reserve of zero means there is none.
+func pickWinner(bids []*closingBid, reserve float64) (*closingBid, AuctionOutcome) {
+	var highest *closingBid
+	for _, b := range bids {
+		if highest == nil || b.Amount > highest.Amount ||
+			(b.Amount == highest.Amount && b.CreatedAt.Before(highest.CreatedAt)) {
+			highest = b
+		}
+	}
+	switch {
+	case highest == nil:
+		return nil, OutcomeNoBids
+	case highest.Amount < reserve:
+		return nil, OutcomeReserveNotMet
+	}
+	return highest, OutcomeSold
+}
+
+// takeItemStock takes the unit of an item sold at auction out of stock.
+func takeItemStock(ctx context.Context, tx *sqldb.Tx, itemID uuid.UUID) error {
+	var remaining int
+	err := tx.QueryRow(ctx, `
+		UPDATE items
+		SET quantity = GREATEST(quantity - 1, 0),
+			status = CASE WHEN quantity <= 1 THEN 'sold' ELSE 'listed' END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING quantity
+	`, itemID).Scan(&remaining)
+	if err != nil {
+		return fmt.Errorf("take item stock: %w", err)
+	}
+	if remaining == 0 {
+		// Selling a lot's listing sells everything grouped into the lot
+		_, err = tx.Exec(ctx, `
+			UPDATE items SET status = 'sold', updated_at = NOW()
+			WHERE lot_id = (SELECT id FROM lots WHERE item_id = $1) AND deleted_at IS NULL
+		`, itemID)
+		if err != nil {
+			return fmt.Errorf("mark lot items sold: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertAuctionOrder creates the order for an auction's buyer, pending
+// payment of amount within paymentWindow.
+func insertAuctionOrder(ctx context.Context, tx *sqldb.Tx, auction *Auction, userID uuid.UUID, amount float64) (uuid.UUID, error) {
+	orderID := uuid.New()
+	_, err := tx.Exec(ctx, `
+		INSERT INTO orders (id, user_id, item_id, auction_id, quantity, total, payment_provider,
+			status, payment_due_at)
+		VALUES ($1, $2, $3, $4, 1, $5, 'stripe', 'pending', $6)
+	`, orderID, userID, auction.ItemID, auction.ID, amount, time.Now().Add(paymentWindow))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create auction order: %w", err)
+	}
+	return orderID, nil
+}
+
+// releaseItem puts an item that didn't sell at auction back on the shelf.
+func releaseItem(ctx context.Context, tx *sqldb.Tx, itemID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE items SET status = 'listed', updated_at = NOW()
+		WHERE id = $1 AND status = 'in_auction'
+	`, itemID)
+	if err != nil {
+		return fmt.Errorf("release item: %w", err)
+	}
+	return nil
+}
+
+// publishClosed announces a closed auction. It runs after the close has
+// committed; a failed publish is logged, as the close itself stands.
+func publishClosed(ctx context.Context, event *AuctionClosedEvent) {
+	if event == nil {
+		return
+	}
+	if _, err := AuctionClosed.Publish(ctx, event); err != nil {
+		rlog.Error("failed to publish auction closed event", "auction_id", event.AuctionID, "err", err)
+	}
+}
@@ -0,0 +1,44 @@
+package auctions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPickWinner(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	bid := func(amount float64, minute int) *closingBid {
+		return &closingBid{ID: uuid.New(), UserID: uuid.New(), Amount: amount, CreatedAt: start.Add(time.Duration(minute) * time.Minute)}
+	}
+	early, late := bid(120, 1), bid(120, 5)
+	low := bid(80, 3)
+
+	testCases := []struct {
+		name     string
+		bids     []*closingBid
+		reserve  float64
+		winner   *closingBid
+		expected AuctionOutcome
+	}{
+		{"no bids", nil, 0, nil, OutcomeNoBids},
+		{"no reserve", []*closingBid{low}, 0, low, OutcomeSold},
+		{"highest wins", []*closingBid{low, early}, 100, early, OutcomeSold},
+		{"earliest wins a tie", []*closingBid{late, low, early}, 0, early, OutcomeSold},
+		{"reserve met exactly", []*closingBid{early}, 120, early, OutcomeSold},
+		{"reserve not met", []*closingBid{low, early}, 150, nil, OutcomeReserveNotMet},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			winner, outcome := pickWinner(tc.bids, tc.reserve)
+			if outcome != tc.expected {
+				t.Errorf("outcome = %s, expected %s", outcome, tc.expected)
+			}
+			if winner != tc.winner {
+				t.Errorf("winner = %+v, expected %+v", winner, tc.winner)
+			}
+		})
+	}
+}
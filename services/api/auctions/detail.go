@@ -0,0 +1,154 @@
+package auctions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRecentBids = 10
+	maxRecentBids     = 50
+)
+
+// BidSummary is a bid as shown on an auction's page. Bidders are anonymized
+// as "Bidder A", "Bidder B" and so on, in the order they first bid.
+type BidSummary struct {
+	Bidder    string    `json:"bidder"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	Mine      bool      `json:"mine,omitempty"` // Placed by the signed-in viewer
+}
+
+// ViewerBidStatus is where the signed-in viewer stands in an auction they
+// have bid on.
+type ViewerBidStatus struct {
+	HighestBid float64 `json:"highest_bid"`
+	BidCount   int     `json:"bid_count"`
+	Status     string  `json:"status"` // "leading" or "outbid" while bidding runs, then "won" or "lost"
+}
+
+// authenticatedViewer returns the signed-in user viewing an auction, or
+// uuid.Nil for anonymous viewers. The viewer only ever comes from auth, never
+// from the request, so nobody can see another bidder's standing.
+func authenticatedViewer() uuid.UUID {
+	uid, ok := auth.UserID()
+	if !ok {
+		return uuid.Nil
+	}
+	id, err := uuid.Parse(string(uid))
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// loadRecentBids returns the latest limit bids on an auction, newest first,
+// marking the viewer's own.
+func loadRecentBids(ctx context.Context, auctionID, viewerID uuid.UUID, limit int) ([]*BidSummary, error) {
+	rows, err := db.Query(ctx, `
+		WITH bidders AS (
+			SELECT user_id, ROW_NUMBER() OVER (ORDER BY MIN(created_at), user_id) AS n
+			FROM bids
+			WHERE auction_id = $1 AND voided_at IS NULL
+			GROUP BY user_id
+		)
+		SELECT bd.n, b.amount, b.created_at, COALESCE(b.user_id = $2, FALSE)
+		FROM bids b
+		JOIN bidders bd ON bd.user_id IS NOT DISTINCT FROM b.user_id
+		WHERE b.auction_id = $1 AND b.voided_at IS NULL
+		ORDER BY b.created_at DESC, b.id
+		LIMIT $3
+	`, auctionID, viewerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query recent bids: %w", err)
+	}
+	defer rows.Close()
+
+	bids := []*BidSummary{}
+	for rows.Next() {
+		var (
+			b BidSummary
+			n int
+		)
+		if err := rows.Scan(&n, &b.Amount, &b.CreatedAt, &b.Mine); err != nil {
+			return nil, fmt.Errorf("scan bid: %w", err)
+		}
+		b.Bidder = bidderLabel(n)
+		bids = append(bids, &b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate recent bids: %w", err)
+	}
+	return bids, nil
+}
+
+// loadViewerBidStatus returns where a user stands in an auction, or nil if
+// they haven't bid on it.
+func loadViewerBidStatus(ctx context.Context, auction *Auction, userID uuid.UUID) (*ViewerBidStatus, error) {
+	var (
+		highest  *float64
+		count    int
+		leading  bool
+		winnerID *uuid.UUID
+	)
+	err := db.QueryRow(ctx, `
+		SELECT MAX(b.amount), COUNT(*),
+			COALESCE((
+				SELECT t.user_id = $2 FROM bids t
+				WHERE t.auction_id = $1 AND t.voided_at IS NULL
+				ORDER BY t.amount DESC, t.created_at
+				LIMIT 1
+			), FALSE),
+			(SELECT w.user_id FROM auctions a JOIN bids w ON w.id = a.winning_bid_id WHERE a.id = $1)
+		FROM bids b
+		WHERE b.auction_id = $1 AND b.user_id = $2 AND b.voided_at IS NULL
+	`, auction.ID, userID).Scan(&highest, &count, &leading, &winnerID)
+	if err != nil {
+		return nil, fmt.Errorf("load viewer bids: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return &ViewerBidStatus{
+		HighestBid: *highest,
+		BidCount:   count,
+		Status:     viewerStatus(AuctionStatus(auction.Status), leading, wonBy(auction, winnerID, userID)),
+	}, nil
+}
+
+// wonBy reports whether userID won an auction whose winning bid was placed
+// by winnerID. Only a sold auction has a winner: a bidder whose order was
+// voided for non-payment has lost it.
+func wonBy(auction *Auction, winnerID *uuid.UUID, userID uuid.UUID) bool {
+	return AuctionOutcome(auction.Outcome) == OutcomeSold && winnerID != nil && *winnerID == userID
+}
+
+// viewerStatus describes a bidder's standing: whether they lead while the
+// auction runs, and whether they won once it has closed.
+func viewerStatus(status AuctionStatus, leading, won bool) string {
+	switch status {
+	case StatusClosed, StatusSettled:
+		if won {
+			return "won"
+		}
+		return "lost"
+	}
+	if leading {
+		return "leading"
+	}
+	return "outbid"
+}
+
+// bidderLabel names the nth bidder in an auction, counting from 1:
+// "Bidder A" to "Bidder Z", then "Bidder AA" and so on.
+func bidderLabel(n int) string {
+	var letters []byte
+	for ; n > 0; n = (n - 1) / 26 {
+		letters = append([]byte{byte('A' + (n-1)%26)}, letters...)
+	}
+	return "Bidder " + string(letters)
+}
@@ -0,0 +1,83 @@
+package auctions
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBidderLabel(t *testing.T) {
+	testCases := []struct {
+		n        int
+		expected string
+	}{
+		{1, "Bidder A"},
+		{2, "Bidder B"},
+		{26, "Bidder Z"},
+		{27, "Bidder AA"},
+		{52, "Bidder AZ"},
+		{53, "Bidder BA"},
+	}
+
+	for _, tc := range testCases {
+		if got := bidderLabel(tc.n); got != tc.expected {
+			t.Errorf("bidderLabel(%d) = %q, expected %q", tc.n, got, tc.expected)
+		}
+	}
+}
+
+func TestViewerStatus(t *testing.T) {
+	testCases := []struct {
+		status       AuctionStatus
+		leading, won bool
+		expected     string
+	}{
+		{StatusOpen, true, false, "leading"},
+		{StatusOpen, false, false, "outbid"},
+		{StatusClosed, true, true, "won"},
+		{StatusClosed, true, false, "lost"}, // Highest bid, but below the reserve
+		{StatusSettled, false, true, "won"}, // Won through a second-chance offer
+		{StatusSettled, false, false, "lost"},
+	}
+
+	for _, tc := range testCases {
+		if got := viewerStatus(tc.status, tc.leading, tc.won); got != tc.expected {
+			t.Errorf("viewerStatus(%s, %v, %v) = %q, expected %q", tc.status, tc.leading, tc.won, got, tc.expected)
+		}
+	}
+}
+
+func TestWonBy(t *testing.T) {
+	winner, runnerUp := uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name     string
+		outcome  AuctionOutcome
+		winnerID *uuid.UUID
+		userID   uuid.UUID
+		expected bool
+	}{
+		{"winner", OutcomeSold, &winner, winner, true},
+		{"another bidder", OutcomeSold, &winner, runnerUp, false},
+		{"order voided", OutcomeSecondChance, nil, winner, false},
+		{"voided with a stale winning bid", OutcomeSecondChance, &winner, winner, false},
+		{"nobody paid", OutcomeUnpaid, nil, winner, false},
+		{"second-chance buyer", OutcomeSold, &runnerUp, runnerUp, true},
+		{"reserve not met", OutcomeReserveNotMet, nil, winner, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: string(StatusClosed), Outcome: string(tc.outcome)}
+			if got := wonBy(auction, tc.winnerID, tc.userID); got != tc.expected {
+				t.Errorf("wonBy() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+
+	// The defaulted winner still holds the highest bid, but has lost
+	voided := &Auction{Status: string(StatusClosed), Outcome: string(OutcomeSecondChance)}
+	if got := viewerStatus(StatusClosed, true, wonBy(voided, nil, winner)); got != "lost" {
+		t.Errorf("Expected a bidder whose order was voided to see lost, got %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package auctions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// auctionTransitions lists the statuses an auction may move to from each
+// status. Settled and cancelled auctions are final; a closed auction that
+// didn't sell stays closed.
+var auctionTransitions = map[AuctionStatus][]AuctionStatus{
+	StatusDraft:     {StatusScheduled, StatusCancelled},
+	StatusScheduled: {StatusOpen, StatusCancelled},
+	StatusOpen:      {StatusClosed, StatusCancelled},
+	StatusClosed:    {StatusSettled},
+	StatusSettled:   {},
+	StatusCancelled: {},
+}
+
+// transitionActions names the audit_log action recorded for entering each
+// status.
+var transitionActions = map[AuctionStatus]string{
+	StatusScheduled: "auction.scheduled",
+	StatusOpen:      "auction.opened",
+	StatusClosed:    "auction.closed",
+	StatusSettled:   "auction.settled",
+	StatusCancelled: "auction.cancelled",
+}
+
+// isValidStatus reports whether s is a known auction status.
+func isValidStatus(s string) bool {
+	_, ok := auctionTransitions[AuctionStatus(s)]
+	return ok
+}
+
+// canTransition reports whether an auction may move from one status to
+// another.
+func canTransition(from, to AuctionStatus) bool {
+	for _, next := range auctionTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError is the error detail returned when an auction is asked to
+// make a status change the lifecycle does not allow, e.g. opening an auction
+// that has already closed.
+type TransitionError struct {
+	AuctionID uuid.UUID     `json:"auction_id"`
+	From      AuctionStatus `json:"from"`
+	To        AuctionStatus `json:"to"`
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("auction %s cannot move from %s to %s", e.AuctionID, e.From, e.To)
+}
+
+// ErrDetails marks TransitionError as structured error details for clients.
+func (*TransitionError) ErrDetails() {}
+
+// transitionMeta is the audit_log meta recorded for a status change.
+type transitionMeta struct {
+	From   AuctionStatus `json:"from,omitempty"`
+	To     AuctionStatus `json:"to"`
+	Reason string        `json:"reason,omitempty"`
+}
+
+// auctionColumns lists the auction columns in the order scanAuction expects
+// them. Queries must alias auctions as "a" and left join lots as "l" (see
+// auctionFrom).
+const auctionColumns = `a.id, a.item_id, l.id, a.starts_at, a.ends_at, COALESCE(a.reserve_price, 0),
+	COALESCE(a.min_increment, 0), a.status, COALESCE(a.anti_sniping_window_sec, 0),
+	a.extension_count, a.watcher_count, COALESCE(a.outcome, ''), a.relisted_from`
+
+const auctionFrom = " FROM auctions a LEFT JOIN lots l ON l.item_id = a.item_id"
+
+// rowScanner is satisfied by both *sqldb.Row and *sqldb.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAuction scans the auctionColumns of row, followed by any extra
+// columns the query selected after them into extra.
+func scanAuction(row rowScanner, extra ...interface{}) (*Auction, error) {
+	var a Auction
+	dest := []interface{}{&a.ID, &a.ItemID, &a.LotID, &a.StartsAt, &a.EndsAt, &a.ReservePrice,
+		&a.MinIncrement, &a.Status, &a.AntiSnipingWindowSec, &a.ExtensionCount, &a.WatcherCount,
+		&a.Outcome, &a.RelistedFrom}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// lockAuction loads an auction for update within tx.
+func lockAuction(ctx context.Context, tx *sqldb.Tx, id string) (*Auction, error) {
+	auctionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid auction id").Err()
+	}
+	auction, err := scanAuction(tx.QueryRow(ctx, "SELECT "+auctionColumns+auctionFrom+" WHERE a.id = $1 FOR UPDATE OF a", auctionID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("auction %s not found", auctionID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load auction: %w", err)
+	}
+	return auction, nil
+}
+
+// transitionAuction moves a locked auction to a new status and records the
+// change in the audit log. Illegal changes fail with a TransitionError
+// detail.
+func transitionAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction, to AuctionStatus, actorID *uuid.UUID, reason string) error {
+	from := AuctionStatus(auction.Status)
+	if !canTransition(from, to) {
+		detail := &TransitionError{AuctionID: auction.ID, From: from, To: to}
+		return errs.B().Code(errs.FailedPrecondition).Msg(detail.Error()).Details(detail).Err()
+	}
+
+	// The status guard catches a concurrent change that slipped past the lock,
+	// e.g. the scheduler on another instance
+	result, err := tx.Exec(ctx, `
+		UPDATE auctions SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, auction.ID, string(to), string(from))
+	if err != nil {
+		return fmt.Errorf("update auction status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errs.B().Code(errs.Aborted).Msgf("auction %s changed concurrently, please retry", auction.ID).Err()
+	}
+	auction.Status = string(to)
+
+	return recordAuctionEvent(ctx, tx, auction.ID, transitionActions[to], actorID, &transitionMeta{From: from, To: to, Reason: reason})
+}
+
+// openLockedAuction opens a scheduled auction for bidding and marks its item
+// as in auction.
+func openLockedAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction) error {
+	if !auction.EndsAt.After(time.Now()) {
+		return errs.B().Code(errs.FailedPrecondition).Msg("the auction's end time has already passed").Err()
+	}
+	if err := transitionAuction(ctx, tx, auction, StatusOpen, nil, ""); err != nil {
+		return err
+	}
+	err := tx.QueryRow(ctx, `
+		UPDATE auctions SET starts_at = LEAST(starts_at, NOW()) WHERE id = $1
+		RETURNING starts_at
+	`, auction.ID).Scan(&auction.StartsAt)
+	if err != nil {
+		return fmt.Errorf("update auction start: %w", err)
+	}
+	result, err := tx.Exec(ctx, `
+		UPDATE items SET status = 'in_auction', updated_at = NOW()
+		WHERE id = $1 AND status = 'listed' AND deleted_at IS NULL
+	`, auction.ItemID)
+	if err != nil {
+		return fmt.Errorf("mark item in auction: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errs.B().Code(errs.FailedPrecondition).Msg("the item is no longer listed").Err()
+	}
+	return nil
+}
+
+// closeLockedAuction ends bidding on an open auction whose end time, including
+// any anti-sniping extensions, has passed, and settles the result. The
+// returned event is published by the caller once the transaction commits.
+func closeLockedAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction) (*AuctionClosedEvent, error) {
+	if auction.Status == string(StatusOpen) && auction.EndsAt.After(time.Now()) {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msgf("auction runs until %s", auction.EndsAt.Format(time.RFC3339)).Err()
+	}
+	if err := transitionAuction(ctx, tx, auction, StatusClosed, nil, ""); err != nil {
+		return nil, err
+	}
+	return settleClose(ctx, tx, auction)
+}
+
+// settleProblem explains why a closed auction with the given outcome and
+// order status can't be settled yet, or returns "" if it can. Only a sold
+// auction whose order has been paid is settled.
+func settleProblem(outcome AuctionOutcome, orderStatus string) string {
+	switch {
+	case outcome != OutcomeSold:
+		return "only sold auctions can be settled"
+	case orderStatus != "paid":
+		return "the winner's order hasn't been paid"
+	}
+	return ""
+}
+
+// recordAuctionEvent writes an audit_log entry for an auction.
+func recordAuctionEvent(ctx context.Context, tx *sqldb.Tx, auctionID uuid.UUID, action string, actorID *uuid.UUID, meta interface{}) error {
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("encode audit meta: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+		VALUES ($1, $2, 'auction', $3, $4)
+	`, actorID, action, auctionID, encoded)
+	if err != nil {
+		return fmt.Errorf("record %s: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package auctions
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCanTransition(t *testing.T) {
+	testCases := []struct {
+		from, to AuctionStatus
+		expected bool
+	}{
+		{StatusDraft, StatusScheduled, true},
+		{StatusDraft, StatusOpen, false},
+		{StatusScheduled, StatusOpen, true},
+		{StatusScheduled, StatusClosed, false},
+		{StatusOpen, StatusClosed, true},
+		{StatusOpen, StatusCancelled, true},
+		{StatusClosed, StatusSettled, true},
+		{StatusClosed, StatusOpen, false},
+		{StatusClosed, StatusCancelled, false},
+		{StatusSettled, StatusCancelled, false},
+		{StatusCancelled, StatusScheduled, false},
+		{"unknown", StatusOpen, false},
+	}
+
+	for _, tc := range testCases {
+		if got := canTransition(tc.from, tc.to); got != tc.expected {
+			t.Errorf("canTransition(%s, %s) = %v, expected %v", tc.from, tc.to, got, tc.expected)
+		}
+	}
+}
+
+func TestEveryStatusHasTransitions(t *testing.T) {
+	for _, status := range []AuctionStatus{StatusDraft, StatusScheduled, StatusOpen, StatusClosed, StatusSettled, StatusCancelled} {
+		if _, ok := auctionTransitions[status]; !ok {
+			t.Errorf("Status %s is missing from auctionTransitions", status)
+		}
+		if status != StatusDraft && transitionActions[status] == "" {
+			t.Errorf("Status %s has no audit action", status)
+		}
+	}
+}
+
+func TestSettleProblem(t *testing.T) {
+	testCases := []struct {
+		outcome     AuctionOutcome
+		orderStatus string
+		settles     bool
+	}{
+		{OutcomeSold, "paid", true},
+		{OutcomeSold, "pending", false},
+		{OutcomeSold, "", false},
+		{OutcomeReserveNotMet, "", false},
+		{OutcomeUnpaid, "", false},
+		{"", "", false}, // Still open
+	}
+
+	for _, tc := range testCases {
+		if got := settleProblem(tc.outcome, tc.orderStatus); (got == "") != tc.settles {
+			t.Errorf("settleProblem(%q, %q) = %q, expected settles=%v", tc.outcome, tc.orderStatus, got, tc.settles)
+		}
+	}
+}
+
+func TestTransitionErrorMessage(t *testing.T) {
+	id := uuid.MustParse("6f1c1a52-8d0e-4a55-9a53-1b2f0e3f9a10")
+	err := &TransitionError{AuctionID: id, From: StatusClosed, To: StatusOpen}
+	if got, want := err.Error(), "auction 6f1c1a52-8d0e-4a55-9a53-1b2f0e3f9a10 cannot move from closed to open"; got != want {
+		t.Errorf("Error() = %q, expected %q", got, want)
+	}
+}
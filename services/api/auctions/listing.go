@@ -0,0 +1,98 @@
+package auctions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// defaultEndingSoonWindow is how close to its end an open auction must be
+	// to count as ending soon when the request doesn't say.
+	defaultEndingSoonWindow = time.Hour
+)
+
+// auctionSorts maps the sort orders GetAuctions accepts to ORDER BY clauses.
+// Ties fall back to the soonest ending so pages are stable.
+var auctionSorts = map[string]string{
+	"ends_at":     " ORDER BY a.ends_at ASC, a.id",
+	"current_bid": " ORDER BY b.current_bid DESC NULLS LAST, a.ends_at ASC, a.id",
+	"bid_count":   " ORDER BY b.bid_count DESC, a.ends_at ASC, a.id",
+	"newest":      " ORDER BY a.created_at DESC, a.id",
+}
+
+// auctionListFrom joins what GetAuctions needs onto auctionFrom: the item,
+// and the current bid and bid count as "b". Voided bids don't count.
+const auctionListFrom = auctionFrom + `
+	JOIN items i ON i.id = a.item_id
+	LEFT JOIN LATERAL (
+		SELECT MAX(amount) AS current_bid, COUNT(*) AS bid_count
+		FROM bids WHERE auction_id = a.id AND voided_at IS NULL
+	) b ON TRUE`
+
+// buildAuctionFilters turns GetAuctions filters into a WHERE clause and its
+// args. Without a status filter, drafts are left out, as they aren't public
+// yet.
+func buildAuctionFilters(req *GetAuctionsRequest, now time.Time) (string, []interface{}) {
+	var (
+		conds = []string{"i.deleted_at IS NULL"}
+		args  []interface{}
+	)
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if req.Status != "" {
+		add("a.status = $%d", req.Status)
+	} else {
+		add("a.status <> $%d", string(StatusDraft))
+	}
+	if req.Category != "" {
+		// Browsing a category includes everything in its subcategories
+		add(`i.category_id IN (
+			WITH RECURSIVE sub AS (
+				SELECT id FROM categories WHERE slug = $%d
+				UNION ALL
+				SELECT c2.id FROM categories c2 JOIN sub ON c2.parent_id = sub.id
+			)
+			SELECT id FROM sub
+		)`, req.Category)
+	}
+	if req.EndingSoon {
+		window := defaultEndingSoonWindow
+		if req.EndingWithinMinutes > 0 {
+			window = time.Duration(req.EndingWithinMinutes) * time.Minute
+		}
+		add("a.status = $%d", string(StatusOpen))
+		add("a.ends_at > $%d", now)
+		add("a.ends_at <= $%d", now.Add(window))
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// normalizePage applies defaults and bounds to 1-based pagination params.
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
+
+// setTimeRemaining fills in how long an open auction has left.
+func setTimeRemaining(auction *Auction, now time.Time) {
+	remaining := auction.EndsAt.Sub(now)
+	if auction.Status == string(StatusOpen) && remaining > 0 {
+		auction.TimeRemaining = ptr(formatDuration(remaining))
+	}
+}
@@ -0,0 +1,64 @@
+package auctions
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildAuctionFilters(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	where, args := buildAuctionFilters(&GetAuctionsRequest{}, now)
+	if !strings.Contains(where, "a.status <> $1") || len(args) != 1 || args[0] != string(StatusDraft) {
+		t.Errorf("Expected drafts to be excluded by default, got %q %v", where, args)
+	}
+
+	where, args = buildAuctionFilters(&GetAuctionsRequest{Status: "closed", Category: "furniture"}, now)
+	if !strings.Contains(where, "a.status = $1") || !strings.Contains(where, "slug = $2") || len(args) != 2 {
+		t.Errorf("Expected status and category filters, got %q %v", where, args)
+	}
+
+	where, args = buildAuctionFilters(&GetAuctionsRequest{EndingSoon: true}, now)
+	if !strings.Contains(where, "a.ends_at <= $4") || len(args) != 4 {
+		t.Fatalf("Expected an ending-soon window, got %q %v", where, args)
+	}
+	if args[3] != now.Add(defaultEndingSoonWindow) {
+		t.Errorf("Expected the default window to end at %v, got %v", now.Add(defaultEndingSoonWindow), args[3])
+	}
+
+	_, args = buildAuctionFilters(&GetAuctionsRequest{EndingSoon: true, EndingWithinMinutes: 15}, now)
+	if args[3] != now.Add(15*time.Minute) {
+		t.Errorf("Expected a 15 minute window to end at %v, got %v", now.Add(15*time.Minute), args[3])
+	}
+}
+
+func TestAuctionSorts(t *testing.T) {
+	for _, sort := range []string{"ends_at", "current_bid", "bid_count", "newest"} {
+		if !strings.HasSuffix(auctionSorts[sort], ", a.id") {
+			t.Errorf("Sort %q should end with a unique tie-breaker, got %q", sort, auctionSorts[sort])
+		}
+	}
+}
+
+func TestSetTimeRemaining(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	open := &Auction{Status: string(StatusOpen), EndsAt: now.Add(90 * time.Minute)}
+	setTimeRemaining(open, now)
+	if open.TimeRemaining == nil || *open.TimeRemaining != "2h0m0s" {
+		t.Errorf("Expected 2h0m0s remaining, got %v", open.TimeRemaining)
+	}
+
+	ended := &Auction{Status: string(StatusOpen), EndsAt: now.Add(-time.Minute)}
+	setTimeRemaining(ended, now)
+	if ended.TimeRemaining != nil {
+		t.Errorf("Expected no time remaining once ended, got %q", *ended.TimeRemaining)
+	}
+
+	scheduled := &Auction{Status: string(StatusScheduled), EndsAt: now.Add(time.Hour)}
+	setTimeRemaining(scheduled, now)
+	if scheduled.TimeRemaining != nil {
+		t.Errorf("Expected no time remaining before opening, got %q", *scheduled.TimeRemaining)
+	}
+}
@@ -0,0 +1,191 @@
+package auctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/cron"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// scheduledStep opens or closes one locked auction that was due. done is
+// false when the auction turned out not to need it after all. A close returns
+// the event to publish once the transaction commits.
+type scheduledStep func(ctx context.Context, tx *sqldb.Tx, auction *Auction) (done bool, closed *AuctionClosedEvent, err error)
+
+// schedulerBatchSize bounds how many auctions one scheduler run opens or
+// closes; anything left over is picked up on the next run.
+const schedulerBatchSize = 200
+
+// The scheduler keeps no state of its own: every run works from the
+// auctions table, so it catches up after downtime, and each auction is
+// handled in its own transaction under a row lock, so overlapping runs on
+// several instances never open or close an auction twice.
+var _ = cron.NewJob("auction-scheduler", cron.JobConfig{
+	Title:    "Open and close auctions on time",
+	Every:    1 * cron.Minute,
+	Endpoint: RunAuctionScheduler,
+})
+
+//encore:api private
+func RunAuctionScheduler(ctx context.Context) error {
+	// AI-CHAT: Opens scheduled auctions at starts_at and closes open ones at
+	// ends_at. Anti-sniping extensions move ends_at itself, so an extended
+	// auction is simply not due yet.
+	opened, err := runDueAuctions(ctx, `
+		SELECT id FROM auctions
+		WHERE status = 'scheduled' AND starts_at <= NOW()
+		ORDER BY starts_at
+		LIMIT $1
+	`, openDueAuction)
+	if err != nil {
+		return err
+	}
+	closed, err := runDueAuctions(ctx, `
+		SELECT id FROM auctions
+		WHERE status = 'open' AND ends_at <= NOW()
+		ORDER BY ends_at
+		LIMIT $1
+	`, closeDueAuction)
+	if err != nil {
+		return err
+	}
+	if opened > 0 || closed > 0 {
+		rlog.Info("auction scheduler run", "opened", opened, "closed", closed)
+	}
+	return nil
+}
+
+// runDueAuctions applies step to each auction id returned by query, counting
+// the ones it handled. A failure on one auction is logged and doesn't stop
+// the others.
+func runDueAuctions(ctx context.Context, query string, step scheduledStep) (int, error) {
+	rows, err := db.Query(ctx, query, schedulerBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("query due auctions: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan auction id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate due auctions: %w", err)
+	}
+
+	handled := 0
+	for _, id := range ids {
+		done, err := runDueAuction(ctx, id, step)
+		if err != nil {
+			rlog.Error("scheduled auction step failed", "auction_id", id, "err", err)
+			continue
+		}
+		if done {
+			handled++
+		}
+	}
+	return handled, nil
+}
+
+// runDueAuction locks one auction and applies step to it. An auction another
+// instance is already working on is skipped rather than waited for.
+func runDueAuction(ctx context.Context, id uuid.UUID, step scheduledStep) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := scanAuction(tx.QueryRow(ctx, "SELECT "+auctionColumns+auctionFrom+`
+		WHERE a.id = $1
+		FOR UPDATE OF a SKIP LOCKED
+	`, id))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("lock auction: %w", err)
+	}
+
+	done, closed, err := step(ctx, tx, auction)
+	if err != nil || !done {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit auction: %w", err)
+	}
+	publishClosed(ctx, closed)
+	return true, nil
+}
+
+// openDueAuction opens a scheduled auction whose start time has come. It is
+// cancelled instead, so staff can relist it, if its whole bidding window
+// passed while the scheduler was down or its item has since been withdrawn.
+func openDueAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction) (bool, *AuctionClosedEvent, error) {
+	now := time.Now()
+	if !dueToOpen(auction, now) {
+		return false, nil, nil // Handled by another run since it was selected
+	}
+
+	var listed bool
+	err := tx.QueryRow(ctx, `
+		SELECT status = 'listed' AND deleted_at IS NULL FROM items WHERE id = $1
+	`, auction.ItemID).Scan(&listed)
+	if err != nil {
+		return false, nil, fmt.Errorf("load item: %w", err)
+	}
+	if reason := openCancelReason(auction, listed, now); reason != "" {
+		if err := transitionAuction(ctx, tx, auction, StatusCancelled, nil, reason); err != nil {
+			return false, nil, err
+		}
+		return true, nil, nil
+	}
+
+	if err := openLockedAuction(ctx, tx, auction); err != nil {
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+// closeDueAuction closes an open auction whose end time has passed.
+func closeDueAuction(ctx context.Context, tx *sqldb.Tx, auction *Auction) (bool, *AuctionClosedEvent, error) {
+	if !dueToClose(auction, time.Now()) {
+		return false, nil, nil // Closed by another run, or extended by a late bid
+	}
+	closed, err := closeLockedAuction(ctx, tx, auction)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, closed, nil
+}
+
+// dueToOpen reports whether a scheduled auction's start time has come.
+func dueToOpen(auction *Auction, now time.Time) bool {
+	return auction.Status == string(StatusScheduled) && !auction.StartsAt.After(now)
+}
+
+// openCancelReason explains why a due auction must be cancelled rather than
+// opened, or returns "" if it can open.
+func openCancelReason(auction *Auction, itemListed bool, now time.Time) string {
+	switch {
+	case !itemListed:
+		return "item is no longer listed"
+	case !auction.EndsAt.After(now):
+		return "bidding window passed before the auction opened"
+	}
+	return ""
+}
+
+// dueToClose reports whether an open auction's end time, including any
+// anti-sniping extensions, has passed.
+func dueToClose(auction *Auction, now time.Time) bool {
+	return auction.Status == string(StatusOpen) && !auction.EndsAt.After(now)
+}
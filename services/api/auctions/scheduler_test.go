@@ -0,0 +1,84 @@
+package auctions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueToOpen(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		status   AuctionStatus
+		startsAt time.Time
+		expected bool
+	}{
+		{"start passed", StatusScheduled, now.Add(-time.Minute), true},
+		{"starts now", StatusScheduled, now, true},
+		{"not yet", StatusScheduled, now.Add(time.Minute), false},
+		{"opened by another run", StatusOpen, now.Add(-time.Minute), false},
+		{"cancelled", StatusCancelled, now.Add(-time.Minute), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: string(tc.status), StartsAt: tc.startsAt, EndsAt: now.Add(time.Hour)}
+			if got := dueToOpen(auction, now); got != tc.expected {
+				t.Errorf("dueToOpen() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOpenCancelReason(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		listed   bool
+		endsAt   time.Time
+		expected string
+	}{
+		{"opens", true, now.Add(time.Hour), ""},
+		{"item withdrawn", false, now.Add(time.Hour), "item is no longer listed"},
+		{"window passed", true, now.Add(-time.Minute), "bidding window passed before the auction opened"},
+		{"ends now", true, now, "bidding window passed before the auction opened"},
+		{"withdrawn and passed", false, now.Add(-time.Minute), "item is no longer listed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: string(StatusScheduled), StartsAt: now.Add(-2 * time.Hour), EndsAt: tc.endsAt}
+			if got := openCancelReason(auction, tc.listed, now); got != tc.expected {
+				t.Errorf("openCancelReason() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDueToClose(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		status   AuctionStatus
+		endsAt   time.Time
+		expected bool
+	}{
+		{"end passed", StatusOpen, now.Add(-time.Minute), true},
+		{"ends now", StatusOpen, now, true},
+		{"extended by a late bid", StatusOpen, now.Add(2 * time.Minute), false},
+		{"closed by another run", StatusClosed, now.Add(-time.Minute), false},
+		{"cancelled", StatusCancelled, now.Add(-time.Minute), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: string(tc.status), EndsAt: tc.endsAt}
+			if got := dueToClose(auction, now); got != tc.expected {
+				t.Errorf("dueToClose() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,605 @@
+package auctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/cron"
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+const (
+	// paymentWindow is how long an auction buyer has to pay for their order
+	// (see POLICY_TERMS.md).
+	paymentWindow = 48 * time.Hour
+
+	// offerWindow is how long a runner-up bidder has to accept a second-chance
+	// offer before it passes to the next bidder.
+	offerWindow = 24 * time.Hour
+)
+
+// Offer is a second-chance offer of an auction's item to a runner-up bidder
+// at their own highest bid, made after the winner failed to pay. Offers are
+// made one at a time, working down the bids.
+type Offer struct {
+	ID          uuid.UUID  `json:"id"`
+	AuctionID   uuid.UUID  `json:"auction_id"`
+	BidID       uuid.UUID  `json:"bid_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Amount      float64    `json:"amount"`
+	Status      string     `json:"status"`
+	OfferedAt   time.Time  `json:"offered_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	OrderID     *uuid.UUID `json:"order_id,omitempty"` // Set once accepted
+}
+
+// OfferStatus defines second-chance offer states
+type OfferStatus string
+
+const (
+	OfferPending  OfferStatus = "pending"
+	OfferAccepted OfferStatus = "accepted"
+	OfferDeclined OfferStatus = "declined"
+	OfferExpired  OfferStatus = "expired"
+)
+
+// SecondChanceOfferEvent is published when an auction's item is offered to
+// a runner-up bidder.
+type SecondChanceOfferEvent struct {
+	OfferID   uuid.UUID `json:"offer_id"`
+	AuctionID uuid.UUID `json:"auction_id"`
+	ItemID    uuid.UUID `json:"item_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SecondChanceOffered is where second-chance offers are announced, so the
+// bidder can be told about them.
+var SecondChanceOffered = pubsub.NewTopic[*SecondChanceOfferEvent]("second-chance-offered", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// SecondChanceAcceptedEvent is published when a runner-up bidder accepts an
+// offer and gets a pending order for the item.
+type SecondChanceAcceptedEvent struct {
+	OfferID   uuid.UUID `json:"offer_id"`
+	AuctionID uuid.UUID `json:"auction_id"`
+	ItemID    uuid.UUID `json:"item_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Amount    float64   `json:"amount"`
+}
+
+// SecondChanceAccepted is where accepted offers are announced, so the new
+// buyer can be sent a link to pay like any auction winner.
+var SecondChanceAccepted = pubsub.NewTopic[*SecondChanceAcceptedEvent]("second-chance-accepted", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// deadlineStep handles one missed deadline on a locked auction: id is the
+// order or offer whose deadline passed. done is false when it turned out to
+// have been dealt with already. A new offer is returned for publishing once
+// the transaction commits.
+type deadlineStep func(ctx context.Context, tx *sqldb.Tx, auction *Auction, id uuid.UUID) (done bool, offer *SecondChanceOfferEvent, err error)
+
+// Like the auction scheduler, the deadline job works from the orders and
+// offers tables on every run and locks the auction for each deadline it
+// handles, so it is safe to run late, twice or on several instances.
+var _ = cron.NewJob("auction-payment-deadlines", cron.JobConfig{
+	Title:    "Void unpaid auction orders and pass them to runner-up bidders",
+	Every:    5 * cron.Minute,
+	Endpoint: RunPaymentDeadlines,
+})
+
+//encore:api private
+func RunPaymentDeadlines(ctx context.Context) error {
+	// AI-CHAT: Voids auction orders not paid within paymentWindow and expires
+	// second-chance offers not answered within offerWindow. Either way the
+	// item is offered to the next-highest bidder, until someone pays or the
+	// bids run out.
+	voided, err := runDeadlines(ctx, `
+		SELECT id, auction_id FROM orders
+		WHERE status = 'pending' AND auction_id IS NOT NULL AND payment_due_at <= NOW()
+		ORDER BY payment_due_at
+		LIMIT $1
+	`, voidUnpaidOrder)
+	if err != nil {
+		return err
+	}
+	expired, err := runDeadlines(ctx, `
+		SELECT id, auction_id FROM auction_offers
+		WHERE status = 'pending' AND expires_at <= NOW()
+		ORDER BY expires_at
+		LIMIT $1
+	`, expireOffer)
+	if err != nil {
+		return err
+	}
+	if voided > 0 || expired > 0 {
+		rlog.Info("payment deadline run", "voided_orders", voided, "expired_offers", expired)
+	}
+	return nil
+}
+
+//encore:api public method=GET path=/v1/auctions/:id/offers
+func GetOffers(ctx context.Context, id string) (*GetOffersResponse, error) {
+	// AI-CHAT: Second-chance offers made for an auction, oldest first
+	auctionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid auction id").Err()
+	}
+	rows, err := db.Query(ctx, "SELECT "+offerColumns+`
+		FROM auction_offers WHERE auction_id = $1
+		ORDER BY offered_at
+	`, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("query offers: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &GetOffersResponse{Offers: []*Offer{}}
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan offer: %w", err)
+		}
+		resp.Offers = append(resp.Offers, offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate offers: %w", err)
+	}
+	return resp, nil
+}
+
+//encore:api public method=POST path=/v1/auctions/:id/offers/:offerID/accept
+func AcceptOffer(ctx context.Context, id string, offerID string, req *RespondToOfferRequest) (*Offer, error) {
+	// AI-CHAT: The offered bidder takes the item at their bid. They get a
+	// pending order with the same payment window as a winner, and the same
+	// email with a link to pay.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, offer, err := lockPendingOffer(ctx, tx, id, offerID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	orderID, err := insertAuctionOrder(ctx, tx, auction, offer.UserID, offer.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := respondToOffer(ctx, tx, offer, OfferAccepted, &orderID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE auctions SET outcome = 'sold', winning_bid_id = $2, updated_at = NOW()
+		WHERE id = $1
+	`, auction.ID, offer.BidID)
+	if err != nil {
+		return nil, fmt.Errorf("record new winner: %w", err)
+	}
+	if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.second_chance_accepted", &offer.UserID, offerMeta(offer)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit offer: %w", err)
+	}
+
+	event := &SecondChanceAcceptedEvent{
+		OfferID:   offer.ID,
+		AuctionID: auction.ID,
+		ItemID:    auction.ItemID,
+		UserID:    offer.UserID,
+		OrderID:   orderID,
+		Amount:    offer.Amount,
+	}
+	if _, err := SecondChanceAccepted.Publish(ctx, event); err != nil {
+		rlog.Error("failed to publish second-chance acceptance", "auction_id", auction.ID, "offer_id", offer.ID, "err", err)
+	}
+	return offer, nil
+}
+
+//encore:api public method=POST path=/v1/auctions/:id/offers/:offerID/decline
+func DeclineOffer(ctx context.Context, id string, offerID string, req *RespondToOfferRequest) (*Offer, error) {
+	// AI-CHAT: The offered bidder passes; the item goes straight to the next
+	// bidder rather than waiting for the offer to expire.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, offer, err := lockPendingOffer(ctx, tx, id, offerID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := respondToOffer(ctx, tx, offer, OfferDeclined, nil); err != nil {
+		return nil, err
+	}
+	if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.second_chance_declined", &offer.UserID, offerMeta(offer)); err != nil {
+		return nil, err
+	}
+	next, err := offerNextBidder(ctx, tx, auction)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit offer: %w", err)
+	}
+	publishOffer(ctx, next)
+	return offer, nil
+}
+
+// runDeadlines applies step to each (id, auction_id) pair returned by query,
+// counting the ones it handled. A failure on one is logged and doesn't stop
+// the others.
+func runDeadlines(ctx context.Context, query string, step deadlineStep) (int, error) {
+	rows, err := db.Query(ctx, query, schedulerBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("query missed deadlines: %w", err)
+	}
+	type deadline struct{ id, auctionID uuid.UUID }
+	var due []deadline
+	for rows.Next() {
+		var d deadline
+		if err := rows.Scan(&d.id, &d.auctionID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan missed deadline: %w", err)
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate missed deadlines: %w", err)
+	}
+
+	handled := 0
+	for _, d := range due {
+		done, err := runDeadline(ctx, d.auctionID, d.id, step)
+		if err != nil {
+			rlog.Error("payment deadline step failed", "auction_id", d.auctionID, "id", d.id, "err", err)
+			continue
+		}
+		if done {
+			handled++
+		}
+	}
+	return handled, nil
+}
+
+// runDeadline locks an auction and applies step to it, skipping an auction
+// another instance is already working on.
+func runDeadline(ctx context.Context, auctionID, id uuid.UUID, step deadlineStep) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := scanAuction(tx.QueryRow(ctx, "SELECT "+auctionColumns+auctionFrom+`
+		WHERE a.id = $1
+		FOR UPDATE OF a SKIP LOCKED
+	`, auctionID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("lock auction: %w", err)
+	}
+
+	done, offer, err := step(ctx, tx, auction, id)
+	if err != nil || !done {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit deadline: %w", err)
+	}
+	publishOffer(ctx, offer)
+	return true, nil
+}
+
+// voidUnpaidOrder voids an auction order whose payment deadline has passed
+// and offers the item to the next bidder.
+func voidUnpaidOrder(ctx context.Context, tx *sqldb.Tx, auction *Auction, orderID uuid.UUID) (bool, *SecondChanceOfferEvent, error) {
+	var (
+		userID *uuid.UUID
+		status string
+		dueAt  *time.Time
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT user_id, status, payment_due_at FROM orders WHERE id = $1 FOR UPDATE
+	`, orderID).Scan(&userID, &status, &dueAt)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return false, nil, nil
+	} else if err != nil {
+		return false, nil, fmt.Errorf("lock order: %w", err)
+	}
+	if !paymentOverdue(status, dueAt, time.Now()) {
+		return false, nil, nil // Paid or voided since it was selected
+	}
+	if _, err := tx.Exec(ctx, "UPDATE orders SET status = 'voided' WHERE id = $1", orderID); err != nil {
+		return false, nil, fmt.Errorf("void order: %w", err)
+	}
+	meta := map[string]interface{}{"order_id": orderID, "user_id": userID}
+	if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.order_voided", nil, meta); err != nil {
+		return false, nil, err
+	}
+	// The bidder who didn't pay is no longer the winner; AcceptOffer records
+	// a new one
+	if err := setAuctionOutcome(ctx, tx, auction, OutcomeSecondChance); err != nil {
+		return false, nil, err
+	}
+
+	offer, err := offerNextBidder(ctx, tx, auction)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, offer, nil
+}
+
+// expireOffer expires a second-chance offer that wasn't answered in time and
+// offers the item to the next bidder.
+func expireOffer(ctx context.Context, tx *sqldb.Tx, auction *Auction, offerID uuid.UUID) (bool, *SecondChanceOfferEvent, error) {
+	offer, err := scanOffer(tx.QueryRow(ctx, "SELECT "+offerColumns+" FROM auction_offers WHERE id = $1 FOR UPDATE", offerID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return false, nil, nil
+	} else if err != nil {
+		return false, nil, fmt.Errorf("lock offer: %w", err)
+	}
+	if !offerExpired(offer, time.Now()) {
+		return false, nil, nil // Answered since it was selected
+	}
+	if err := respondToOffer(ctx, tx, offer, OfferExpired, nil); err != nil {
+		return false, nil, err
+	}
+	if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.second_chance_expired", nil, offerMeta(offer)); err != nil {
+		return false, nil, err
+	}
+
+	next, err := offerNextBidder(ctx, tx, auction)
+	if err != nil {
+		return false, nil, err
+	}
+	return true, next, nil
+}
+
+// offerNextBidder offers a closed auction's item to the highest remaining
+// bidder at their own highest bid, skipping anyone who has already had an
+// order or an offer for it. Bids below the reserve don't qualify. Once no
+// bidders are left the item goes back on the shelf and the auction's
+// outcome becomes OutcomeUnpaid.
+func offerNextBidder(ctx context.Context, tx *sqldb.Tx, auction *Auction) (*SecondChanceOfferEvent, error) {
+	if auction.Status != string(StatusClosed) {
+		return nil, nil
+	}
+
+	bids, err := loadClosingBids(ctx, tx, auction)
+	if err != nil {
+		return nil, err
+	}
+	passed, err := loadPassedBidders(ctx, tx, auction.ID)
+	if err != nil {
+		return nil, err
+	}
+	next := nextSecondChanceBid(bids, passed, auction.ReservePrice)
+	if next == nil {
+		return nil, restockUnpaidItem(ctx, tx, auction)
+	}
+
+	event := &SecondChanceOfferEvent{
+		AuctionID: auction.ID,
+		ItemID:    auction.ItemID,
+		UserID:    next.UserID,
+		Amount:    next.Amount,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO auction_offers (auction_id, bid_id, user_id, amount, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, expires_at
+	`, auction.ID, next.ID, next.UserID, next.Amount, time.Now().Add(offerWindow)).Scan(&event.OfferID, &event.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert offer: %w", err)
+	}
+	meta := map[string]interface{}{"offer_id": event.OfferID, "user_id": next.UserID, "amount": next.Amount}
+	if err := recordAuctionEvent(ctx, tx, auction.ID, "auction.second_chance_offered", nil, meta); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// loadPassedBidders returns the bidders who have already had their chance at
+// an auction's item: the winner and anyone offered it since.
+func loadPassedBidders(ctx context.Context, tx *sqldb.Tx, auctionID uuid.UUID) (map[uuid.UUID]bool, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT user_id FROM orders WHERE auction_id = $1 AND user_id IS NOT NULL
+		UNION
+		SELECT user_id FROM auction_offers WHERE auction_id = $1
+	`, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("query passed bidders: %w", err)
+	}
+	defer rows.Close()
+
+	passed := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("scan passed bidder: %w", err)
+		}
+		passed[userID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate passed bidders: %w", err)
+	}
+	return passed, nil
+}
+
+// nextSecondChanceBid returns the bid to make the next second-chance offer
+// on: the highest bid from a bidder who hasn't had their chance yet, the
+// earliest winning a tie. It is nil when no such bid meets the reserve.
+func nextSecondChanceBid(bids []*closingBid, passed map[uuid.UUID]bool, reserve float64) *closingBid {
+	var remaining []*closingBid
+	for _, b := range bids {
+		if !passed[b.UserID] {
+			remaining = append(remaining, b)
+		}
+	}
+	next, _ := pickWinner(remaining, reserve)
+	return next
+}
+
+// paymentOverdue reports whether an auction order is still awaiting payment
+// after its deadline.
+func paymentOverdue(status string, dueAt *time.Time, now time.Time) bool {
+	return status == "pending" && dueAt != nil && !dueAt.After(now)
+}
+
+// offerExpired reports whether a second-chance offer went unanswered past its
+// deadline.
+func offerExpired(offer *Offer, now time.Time) bool {
+	return offer.Status == string(OfferPending) && !offer.ExpiresAt.After(now)
+}
+
+// offerResponseProblem checks that userID may answer offer now. It returns
+// errs.OK, or the error code and message to reject the response with.
+func offerResponseProblem(offer *Offer, userID uuid.UUID, now time.Time) (errs.ErrCode, string) {
+	switch {
+	case offer.UserID != userID:
+		return errs.PermissionDenied, "the offer was made to another bidder"
+	case offer.Status != string(OfferPending):
+		return errs.FailedPrecondition, fmt.Sprintf("the offer is already %s", offer.Status)
+	case !offer.ExpiresAt.After(now):
+		return errs.FailedPrecondition, "the offer has expired"
+	}
+	return errs.OK, ""
+}
+
+// restockUnpaidItem puts back the unit taken when the auction closed, once
+// nobody is left to buy it.
+func restockUnpaidItem(ctx context.Context, tx *sqldb.Tx, auction *Auction) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE items
+		SET quantity = quantity + 1,
+			status = CASE WHEN status = 'sold' THEN 'listed' ELSE status END,
+			updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, auction.ItemID)
+	if err != nil {
+		return fmt.Errorf("restock item: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE items SET status = 'listed', updated_at = NOW()
+		WHERE lot_id = (SELECT id FROM lots WHERE item_id = $1) AND status = 'sold' AND deleted_at IS NULL
+	`, auction.ItemID)
+	if err != nil {
+		return fmt.Errorf("restock lot items: %w", err)
+	}
+	if err := setAuctionOutcome(ctx, tx, auction, OutcomeUnpaid); err != nil {
+		return err
+	}
+	return recordAuctionEvent(ctx, tx, auction.ID, "auction.unpaid", nil, map[string]string{"reason": "no remaining bidder paid"})
+}
+
+// setAuctionOutcome moves a closed auction to an outcome without a winner.
+func setAuctionOutcome(ctx context.Context, tx *sqldb.Tx, auction *Auction, outcome AuctionOutcome) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE auctions SET outcome = $2, winning_bid_id = NULL, updated_at = NOW() WHERE id = $1
+	`, auction.ID, string(outcome))
+	if err != nil {
+		return fmt.Errorf("record auction outcome: %w", err)
+	}
+	auction.Outcome = string(outcome)
+	return nil
+}
+
+// lockPendingOffer locks an auction and one of its offers for a response
+// from the offered bidder. Only a pending offer that has not yet expired can
+// be answered.
+func lockPendingOffer(ctx context.Context, tx *sqldb.Tx, id, offerID string, userID uuid.UUID) (*Auction, *Offer, error) {
+	oid, err := uuid.Parse(offerID)
+	if err != nil {
+		return nil, nil, errs.B().Code(errs.InvalidArgument).Msg("invalid offer id").Err()
+	}
+	auction, err := lockAuction(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	offer, err := scanOffer(tx.QueryRow(ctx, "SELECT "+offerColumns+`
+		FROM auction_offers WHERE id = $1 AND auction_id = $2
+		FOR UPDATE
+	`, oid, auction.ID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, nil, errs.B().Code(errs.NotFound).Msgf("offer %s not found", oid).Err()
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("load offer: %w", err)
+	}
+	if code, msg := offerResponseProblem(offer, userID, time.Now()); code != errs.OK {
+		return nil, nil, errs.B().Code(code).Msg(msg).Err()
+	}
+	return auction, offer, nil
+}
+
+// respondToOffer records the bidder's answer to a locked offer.
+func respondToOffer(ctx context.Context, tx *sqldb.Tx, offer *Offer, status OfferStatus, orderID *uuid.UUID) error {
+	err := tx.QueryRow(ctx, `
+		UPDATE auction_offers SET status = $2, responded_at = NOW(), order_id = $3
+		WHERE id = $1
+		RETURNING responded_at
+	`, offer.ID, string(status), orderID).Scan(&offer.RespondedAt)
+	if err != nil {
+		return fmt.Errorf("update offer: %w", err)
+	}
+	offer.Status = string(status)
+	offer.OrderID = orderID
+	return nil
+}
+
+// offerMeta is the audit_log meta recorded for an offer.
+func offerMeta(offer *Offer) map[string]interface{} {
+	meta := map[string]interface{}{"offer_id": offer.ID, "user_id": offer.UserID, "amount": offer.Amount}
+	if offer.OrderID != nil {
+		meta["order_id"] = offer.OrderID
+	}
+	return meta
+}
+
+// publishOffer announces a new second-chance offer. It runs after the offer
+// has committed; a failed publish is logged, as the offer itself stands.
+func publishOffer(ctx context.Context, event *SecondChanceOfferEvent) {
+	if event == nil {
+		return
+	}
+	if _, err := SecondChanceOffered.Publish(ctx, event); err != nil {
+		rlog.Error("failed to publish second-chance offer", "auction_id", event.AuctionID, "offer_id", event.OfferID, "err", err)
+	}
+}
+
+const offerColumns = `id, auction_id, bid_id, user_id, amount, status, offered_at, expires_at,
+	responded_at, order_id`
+
+func scanOffer(row rowScanner) (*Offer, error) {
+	var o Offer
+	err := row.Scan(&o.ID, &o.AuctionID, &o.BidID, &o.UserID, &o.Amount, &o.Status, &o.OfferedAt,
+		&o.ExpiresAt, &o.RespondedAt, &o.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+type RespondToOfferRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetOffersResponse struct {
+	Offers []*Offer `json:"offers"`
+}
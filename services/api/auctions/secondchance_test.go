@@ -0,0 +1,134 @@
+package auctions
+
+import (
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/google/uuid"
+)
+
+func TestNextSecondChanceBid(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	winner, second, third := uuid.New(), uuid.New(), uuid.New()
+	bid := func(userID uuid.UUID, amount float64, minute int) *closingBid {
+		return &closingBid{ID: uuid.New(), UserID: userID, Amount: amount, CreatedAt: start.Add(time.Duration(minute) * time.Minute)}
+	}
+	winning := bid(winner, 150, 9)
+	secondBest, secondLow := bid(second, 140, 8), bid(second, 100, 2)
+	thirdBest := bid(third, 120, 6)
+	thirdTie := bid(third, 140, 10)
+	bids := []*closingBid{secondLow, thirdBest, secondBest, winning}
+
+	testCases := []struct {
+		name     string
+		bids     []*closingBid
+		passed   []uuid.UUID
+		reserve  float64
+		expected *closingBid
+	}{
+		{"runner-up's best bid", bids, []uuid.UUID{winner}, 0, secondBest},
+		{"works down the bidders", bids, []uuid.UUID{winner, second}, 0, thirdBest},
+		{"nobody left", bids, []uuid.UUID{winner, second, third}, 0, nil},
+		{"no bids", nil, nil, 0, nil},
+		{"reserve met exactly", bids, []uuid.UUID{winner, second}, 120, thirdBest},
+		{"remaining bids below reserve", bids, []uuid.UUID{winner, second}, 130, nil},
+		{"earliest wins a tie", append(bids, thirdTie), []uuid.UUID{winner}, 0, secondBest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			passed := make(map[uuid.UUID]bool)
+			for _, id := range tc.passed {
+				passed[id] = true
+			}
+			if got := nextSecondChanceBid(tc.bids, passed, tc.reserve); got != tc.expected {
+				t.Errorf("nextSecondChanceBid() = %+v, expected %+v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPaymentOverdue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	testCases := []struct {
+		name     string
+		status   string
+		dueAt    *time.Time
+		expected bool
+	}{
+		{"deadline passed", "pending", at(-time.Minute), true},
+		{"due now", "pending", at(0), true},
+		{"still time to pay", "pending", at(time.Hour), false},
+		{"paid", "paid", at(-time.Minute), false},
+		{"already voided", "voided", at(-time.Minute), false},
+		{"no deadline", "pending", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := paymentOverdue(tc.status, tc.dueAt, now); got != tc.expected {
+				t.Errorf("paymentOverdue() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOfferExpired(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		status    OfferStatus
+		expiresAt time.Time
+		expected  bool
+	}{
+		{"unanswered past deadline", OfferPending, now.Add(-time.Minute), true},
+		{"expires now", OfferPending, now, true},
+		{"still open", OfferPending, now.Add(time.Hour), false},
+		{"accepted in time", OfferAccepted, now.Add(-time.Minute), false},
+		{"declined", OfferDeclined, now.Add(-time.Minute), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			offer := &Offer{Status: string(tc.status), ExpiresAt: tc.expiresAt}
+			if got := offerExpired(offer, now); got != tc.expected {
+				t.Errorf("offerExpired() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOfferResponseProblem(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	bidder := uuid.New()
+
+	testCases := []struct {
+		name      string
+		userID    uuid.UUID
+		status    OfferStatus
+		expiresAt time.Time
+		expected  errs.ErrCode
+	}{
+		{"offered bidder in time", bidder, OfferPending, now.Add(time.Hour), errs.OK},
+		{"another user", uuid.New(), OfferPending, now.Add(time.Hour), errs.PermissionDenied},
+		{"already declined", bidder, OfferDeclined, now.Add(time.Hour), errs.FailedPrecondition},
+		{"expired", bidder, OfferPending, now, errs.FailedPrecondition},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			offer := &Offer{UserID: bidder, Status: string(tc.status), ExpiresAt: tc.expiresAt}
+			code, msg := offerResponseProblem(offer, tc.userID, now)
+			if code != tc.expected {
+				t.Errorf("offerResponseProblem() = %s %q, expected %s", code, msg, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package bidding holds the bidding rules shared by the auctions and bids
+// services: how much each bid must raise the last one and when a late bid
+// extends an auction.
+package bidding
+
+import (
+	"math"
+	"time"
+)
+
+// MaxExtensions caps how many times late bids can extend one auction, so a
+// bidding war can't keep it open indefinitely.
+const MaxExtensions = 10
+
+// TierIncrement is the minimum raise over currentBid under the tiered
+// increment rules, which keep auctions moving without penny bidding wars.
+func TierIncrement(currentBid float64) float64 {
+	switch {
+	case currentBid < 50:
+		return 1.0
+	case currentBid < 200:
+		return 5.0
+	case currentBid < 500:
+		return 10.0
+	default:
+		return 25.0
+	}
+}
+
+// MinIncrement is the raise a bid must make over currentBid: the tiered
+// increment, or the auction's own minimum increment if that is larger.
+func MinIncrement(currentBid, auctionMinIncrement float64) float64 {
+	return math.Max(TierIncrement(currentBid), auctionMinIncrement)
+}
+
+// NextMinimumBid is the lowest amount an auction accepts as its next bid.
+// currentBid is nil before the first bid, which must be at least one
+// increment.
+func NextMinimumBid(currentBid *float64, auctionMinIncrement float64) float64 {
+	current := 0.0
+	if currentBid != nil {
+		current = *currentBid
+	}
+	return roundCents(current + MinIncrement(current, auctionMinIncrement))
+}
+
+// ExtendedEnd applies the anti-sniping rule to a bid placed at placedAt on
+// an auction ending at endsAt: a bid within window of the end pushes the end
+// back by window (see POLICY_TERMS.md), unless the auction has already been
+// extended MaxExtensions times. It returns the auction's end time after the
+// bid and whether the bid extended it.
+func ExtendedEnd(endsAt, placedAt time.Time, window time.Duration, extensions int) (time.Time, bool) {
+	if window <= 0 || extensions >= MaxExtensions || !placedAt.Before(endsAt) || endsAt.Sub(placedAt) > window {
+		return endsAt, false
+	}
+	return endsAt.Add(window), true
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,69 @@
+package bidding
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMinIncrement(t *testing.T) {
+	testCases := []struct {
+		currentBid, auctionMin, expected float64
+	}{
+		{25, 0, 1},
+		{25, 5, 5},
+		{150, 5, 5},
+		{350, 5, 10},
+		{350, 20, 20},
+		{750, 5, 25},
+	}
+
+	for _, tc := range testCases {
+		if got := MinIncrement(tc.currentBid, tc.auctionMin); got != tc.expected {
+			t.Errorf("MinIncrement(%v, %v) = %v, expected %v", tc.currentBid, tc.auctionMin, got, tc.expected)
+		}
+	}
+}
+
+func TestNextMinimumBid(t *testing.T) {
+	if got := NextMinimumBid(nil, 5); got != 5 {
+		t.Errorf("Expected an opening minimum of 5, got %v", got)
+	}
+	if got := NextMinimumBid(nil, 0); got != 1 {
+		t.Errorf("Expected an opening minimum of 1 without an auction increment, got %v", got)
+	}
+	current := 199.99
+	if got := NextMinimumBid(&current, 0); got != 204.99 {
+		t.Errorf("Expected 204.99 after a bid of 199.99, got %v", got)
+	}
+}
+
+func TestExtendedEnd(t *testing.T) {
+	endsAt := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	testCases := []struct {
+		name       string
+		placedAt   time.Time
+		window     time.Duration
+		extensions int
+		expected   time.Time
+		extended   bool
+	}{
+		{"well before the end", endsAt.Add(-5 * time.Minute), window, 0, endsAt, false},
+		{"just outside the window", endsAt.Add(-window - time.Second), window, 0, endsAt, false},
+		{"on the window boundary", endsAt.Add(-window), window, 0, endsAt.Add(window), true},
+		{"in the final seconds", endsAt.Add(-time.Second), window, 3, endsAt.Add(window), true},
+		{"at the end", endsAt, window, 0, endsAt, false},
+		{"extension cap reached", endsAt.Add(-time.Second), window, MaxExtensions, endsAt, false},
+		{"anti-sniping disabled", endsAt.Add(-time.Second), 0, 0, endsAt, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, extended := ExtendedEnd(endsAt, tc.placedAt, tc.window, tc.extensions)
+			if !got.Equal(tc.expected) || extended != tc.extended {
+				t.Errorf("ExtendedEnd() = %v, %v, expected %v, %v", got, extended, tc.expected, tc.extended)
+			}
+		})
+	}
+}
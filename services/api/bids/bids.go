@@ -2,11 +2,17 @@ package bids
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
 	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
 	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/bidding"
 )
 
 // Bid represents a user's bid on an auction
@@ -30,29 +36,118 @@ func PlaceBid(ctx context.Context, auctionID string, req *PlaceBidRequest) (*Pla
 	// - Minimum increment enforcement based on bid tiers
 	// - Instant outbid notifications via email/SMS
 	// - AI-powered bidding strategy suggestions
+	// The auction row stays locked from the checks until the bid and any
+	// extension commit, so concurrent bids are taken one at a time and the
+	// auction can't close in between.
 
-	// TODO: Validate auction is open and user is authenticated
-	// TODO: Check minimum increment requirements
-	// TODO: Handle anti-sniping extension logic
 	// TODO: Send outbid notifications to previous high bidder
 	// TODO: Broadcast real-time update to all auction watchers
 
+	id, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid auction id").Err()
+	}
+	if err := validateBid(id, req.UserID, req.Amount); err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg(err.Error()).Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		itemID       uuid.UUID
+		status       string
+		endsAt       time.Time
+		minIncrement float64
+		windowSec    int
+		extensions   int
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT item_id, status, ends_at, COALESCE(min_increment, 0),
+			COALESCE(anti_sniping_window_sec, 0), extension_count
+		FROM auctions WHERE id = $1
+		FOR UPDATE
+	`, id).Scan(&itemID, &status, &endsAt, &minIncrement, &windowSec, &extensions)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("auction %s not found", id).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("lock auction: %w", err)
+	}
+
+	// Taken under the lock, so bids are timestamped in the order they land
+	placedAt := time.Now()
+	if status != "open" {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msgf("the auction is %s, not open for bidding", status).Err()
+	}
+	if !placedAt.Before(endsAt) {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("the auction has ended").Err()
+	}
+
+	var currentBid *float64
+	err = tx.QueryRow(ctx, `
+		SELECT MAX(amount) FROM bids WHERE auction_id = $1 AND voided_at IS NULL
+	`, id).Scan(&currentBid)
+	if err != nil {
+		return nil, fmt.Errorf("load current bid: %w", err)
+	}
+	if minBid := bidding.NextMinimumBid(currentBid, minIncrement); req.Amount < minBid {
+		detail := &BidTooLowError{MinimumBid: minBid}
+		return nil, errs.B().Code(errs.InvalidArgument).Msg(detail.Error()).Details(detail).Err()
+	}
+
 	bid := &Bid{
 		ID:        uuid.New(),
-		AuctionID: uuid.MustParse(auctionID),
+		AuctionID: id,
 		UserID:    req.UserID,
 		Amount:    req.Amount,
-		CreatedAt: time.Now(),
-		IsWinning: true, // Assume this becomes winning bid
+		CreatedAt: placedAt,
+		IsWinning: true, // It clears the previous high bid by at least one increment
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO bids (id, auction_id, user_id, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, bid.ID, bid.AuctionID, bid.UserID, bid.Amount, bid.CreatedAt)
+	if sqldb.ErrCode(err) == sqlerr.ForeignKeyViolation {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("user does not exist").Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("insert bid: %w", err)
 	}
 
-	return &PlaceBidResponse{
-		Bid:       bid,
-		IsWinning: true,
-		Message:   "Bid placed successfully! You are now the highest bidder.",
+	resp := &PlaceBidResponse{
+		Bid:        bid,
+		IsWinning:  true,
+		Message:    "Bid placed successfully! You are now the highest bidder.",
+		EndsAt:     endsAt,
+		NextMinBid: bidding.NextMinimumBid(&bid.Amount, minIncrement),
 		// AI-CHAT: Success message can include AI tips like:
 		// "Great bid! This Herman Miller chair typically sells for $400+ new. You're getting excellent value."
-	}, nil
+	}
+
+	var extended *AuctionExtendedEvent
+	window := time.Duration(windowSec) * time.Second
+	if newEnd, ok := bidding.ExtendedEnd(endsAt, placedAt, window, extensions); ok {
+		extended, err = extendAuction(ctx, tx, id, itemID, bid, endsAt, newEnd)
+		if err != nil {
+			return nil, err
+		}
+		resp.EndsAt = extended.EndsAt
+		resp.Extended = true
+		resp.Message = fmt.Sprintf("Bid placed successfully! You are now the highest bidder. The auction has been extended to %s.",
+			extended.EndsAt.Format(time.Kitchen))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit bid: %w", err)
+	}
+	if extended != nil {
+		if _, err := AuctionExtended.Publish(ctx, extended); err != nil {
+			rlog.Error("failed to publish auction extended event", "auction_id", id, "err", err)
+		}
+	}
+	return resp, nil
 }
 
 //encore:api public method=GET path=/v1/auctions/:auctionID/bids
@@ -119,26 +214,24 @@ func GetUserBids(ctx context.Context, userID string, req *GetUserBidsRequest) (*
 func calculateMinIncrement(currentBid float64) float64 {
 	// AI-CHAT: Tiered increment system prevents penny bidding wars
 	// Keeps auctions moving while allowing competitive bidding
-	switch {
-	case currentBid < 50:
-		return 1.0
-	case currentBid < 200:
-		return 5.0
-	case currentBid < 500:
-		return 10.0
-	default:
-		return 25.0
-	}
+	// The tiers live in the bidding package so auction pages quote the same
+	// next minimum bid that PlaceBid enforces.
+	return bidding.TierIncrement(currentBid)
 }
 
 // validateBid checks if bid meets all requirements
 func validateBid(auctionID uuid.UUID, userID uuid.UUID, amount float64) error {
-	// TODO: Implement full validation logic
-	// - Check auction is open
-	// - Verify user is not the seller
-	// - Ensure bid meets minimum increment
-	// - Check user has sufficient funds/authorization
-	
+	// Whether the auction is open and the amount clears the minimum
+	// increment are checked by PlaceBid with the auction locked.
+	// TODO: Verify user is not the seller
+	// TODO: Check user has sufficient funds/authorization
+
+	if auctionID == uuid.Nil {
+		return fmt.Errorf("auction id is required")
+	}
+	if userID == uuid.Nil {
+		return fmt.Errorf("user id is required")
+	}
 	if amount <= 0 {
 		return fmt.Errorf("bid amount must be positive")
 	}
@@ -153,9 +246,12 @@ type PlaceBidRequest struct {
 }
 
 type PlaceBidResponse struct {
-	Bid       *Bid   `json:"bid"`
-	IsWinning bool   `json:"is_winning"`
-	Message   string `json:"message"`
+	Bid        *Bid      `json:"bid"`
+	IsWinning  bool      `json:"is_winning"`
+	Message    string    `json:"message"`
+	EndsAt     time.Time `json:"ends_at"`      // The auction's end time after this bid
+	Extended   bool      `json:"extended"`     // The bid triggered an anti-sniping extension
+	NextMinBid float64   `json:"next_min_bid"`
 }
 
 type GetBidsRequest struct {
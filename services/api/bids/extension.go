@@ -0,0 +1,83 @@
+package bids
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"encore.dev/pubsub"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// AuctionExtendedEvent is published when a late bid pushes back the end of
+// an auction under the anti-sniping rule.
+type AuctionExtendedEvent struct {
+	AuctionID      uuid.UUID `json:"auction_id"`
+	ItemID         uuid.UUID `json:"item_id"`
+	BidID          uuid.UUID `json:"bid_id"`
+	BidderID       uuid.UUID `json:"bidder_id"` // Placed the bid that caused the extension
+	PreviousEndsAt time.Time `json:"previous_ends_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	ExtensionCount int       `json:"extension_count"`
+}
+
+// AuctionExtended is where anti-sniping extensions are announced, so
+// watchers can be told the new end time.
+var AuctionExtended = pubsub.NewTopic[*AuctionExtendedEvent]("auction-extended", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+// BidTooLowError is the error detail returned for a bid below the auction's
+// next minimum bid.
+type BidTooLowError struct {
+	MinimumBid float64 `json:"minimum_bid"`
+}
+
+func (e *BidTooLowError) Error() string {
+	return fmt.Sprintf("bid must be at least $%.2f", e.MinimumBid)
+}
+
+// ErrDetails marks BidTooLowError as structured error details for clients.
+func (*BidTooLowError) ErrDetails() {}
+
+// extendAuction moves a locked auction's end time to endsAt after bid landed
+// in its anti-sniping window, counts the extension and records it in the
+// audit log. It returns the event to publish once the transaction commits.
+func extendAuction(ctx context.Context, tx *sqldb.Tx, auctionID, itemID uuid.UUID, bid *Bid, previous, endsAt time.Time) (*AuctionExtendedEvent, error) {
+	event := &AuctionExtendedEvent{
+		AuctionID:      auctionID,
+		ItemID:         itemID,
+		BidID:          bid.ID,
+		BidderID:       bid.UserID,
+		PreviousEndsAt: previous,
+	}
+	err := tx.QueryRow(ctx, `
+		UPDATE auctions
+		SET ends_at = $2, extension_count = extension_count + 1, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ends_at, extension_count
+	`, auctionID, endsAt).Scan(&event.EndsAt, &event.ExtensionCount)
+	if err != nil {
+		return nil, fmt.Errorf("extend auction: %w", err)
+	}
+
+	meta, err := json.Marshal(map[string]interface{}{
+		"bid_id":          bid.ID,
+		"from":            previous,
+		"to":              event.EndsAt,
+		"extension_count": event.ExtensionCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode audit meta: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+		VALUES ($1, 'auction.extended', 'auction', $2, $3)
+	`, bid.UserID, auctionID, meta)
+	if err != nil {
+		return nil, fmt.Errorf("record auction.extended: %w", err)
+	}
+	return event, nil
+}
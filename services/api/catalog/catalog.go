@@ -2,34 +2,61 @@ package catalog
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
 	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
 	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
 )
 
 // Item represents a cataloged item for auction or sale
 type Item struct {
-	ID          uuid.UUID   `json:"id" db:"id"`
-	Slug        string      `json:"slug" db:"slug"`
-	Title       string      `json:"title" db:"title"`
-	Description string      `json:"description" db:"description"`
-	CategoryID  uuid.UUID   `json:"category_id" db:"category_id"`
-	Condition   string      `json:"condition" db:"condition"`
-	Images      []string    `json:"images" db:"images"`
-	Location    string      `json:"location" db:"location"`
-	Dimensions  *Dimensions `json:"dimensions,omitempty" db:"dimensions"`
-	Weight      *float64    `json:"weight,omitempty" db:"weight"`
-	BuyNowPrice *float64    `json:"buy_now_price,omitempty" db:"buy_now_price"`
-	CreatedBy   uuid.UUID   `json:"created_by" db:"created_by"`
-	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
-}
-
-// Category represents item categories
+	ID               uuid.UUID        `json:"id" db:"id"`
+	Slug             string           `json:"slug" db:"slug"`
+	SKU              string           `json:"sku" db:"sku"` // Printed on the item's label, e.g. "SR-0016J"
+	Title            string           `json:"title" db:"title"`
+	Description      string           `json:"description" db:"description"`
+	CategoryID       uuid.UUID        `json:"category_id" db:"category_id"`
+	Condition        string           `json:"condition" db:"condition"`
+	Images           []string         `json:"images" db:"images"` // Legacy filenames; uploads are listed in Photos
+	Location         string           `json:"location" db:"location"`
+	Dimensions       *Dimensions      `json:"dimensions,omitempty" db:"dimensions"`
+	Weight           *float64         `json:"weight,omitempty" db:"weight"`
+	BuyNowPrice      *float64         `json:"buy_now_price,omitempty" db:"buy_now_price"`
+	Quantity         int              `json:"quantity" db:"quantity"` // Units on hand, e.g. 40 identical monitor arms
+	Status           string           `json:"status" db:"status"`
+	CreatedBy        uuid.UUID        `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt        *time.Time       `json:"updated_at,omitempty" db:"updated_at"`
+	DeletedAt        *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`
+	LotID            *uuid.UUID       `json:"lot_id,omitempty" db:"lot_id"`               // Set on items grouped into a lot
+	BinID            *uuid.UUID       `json:"bin_id,omitempty" db:"bin_id"`               // Warehouse bin the item is shelved in
+	FlaggedForReview bool             `json:"flagged_for_review" db:"flagged_for_review"` // Matched a restricted term; cannot be listed until approved
+	WatcherCount     int              `json:"watcher_count" db:"watcher_count"`           // Users following the item
+	ViewCount        int              `json:"view_count,omitempty"`                       // Distinct sessions that viewed the item, on item pages only
+	Photos           []*ItemImage     `json:"photos,omitempty"`
+	Lot              *Lot             `json:"lot,omitempty"`              // Set when this item is the listing for a lot
+	ConditionReport  *ConditionReport `json:"condition_report,omitempty"` // Latest grading, on item detail
+}
+
+// Category represents item categories, optionally nested under a parent
+// (e.g. Electronics > Monitors)
 type Category struct {
-	ID   uuid.UUID `json:"id" db:"id"`
-	Name string    `json:"name" db:"name"`
-	Slug string    `json:"slug" db:"slug"`
+	ID             uuid.UUID   `json:"id" db:"id"`
+	Name           string      `json:"name" db:"name"`
+	Slug           string      `json:"slug" db:"slug"`
+	ParentID       *uuid.UUID  `json:"parent_id,omitempty" db:"parent_id"`
+	ItemCount      int         `json:"item_count"`
+	TotalItemCount int         `json:"total_item_count"` // Includes items in subcategories
+	Children       []*Category `json:"children,omitempty"`
 }
 
 // Dimensions represents item physical dimensions
@@ -57,41 +84,93 @@ var db = sqldb.Named("seattle_reuse")
 func GetItems(ctx context.Context, req *GetItemsRequest) (*GetItemsResponse, error) {
 	// AI-CHAT: Main product browsing endpoint with advanced filtering
 	// Supports category, condition, price range, and location filters
-	// Integrates with Meilisearch for instant search and typo tolerance
+	// Free-text search goes through the pluggable search index with typo tolerance
 	// Returns items with AI-enhanced descriptions and condition assessments
 
-	// TODO: Implement database query with filters
-	// TODO: Integrate with Meilisearch for search
-	// TODO: Return paginated results
-
-	items := []*Item{
-		{
-			ID:          uuid.New(),
-			Slug:        "herman-miller-aeron-chair",
-			Title:       "Herman Miller Aeron Chair",
-			Description: "Classic ergonomic office chair in good condition. Shows normal wear but fully functional. Great for home office or startup.",
-			// AI-CHAT: AI can provide detailed info about this chair:
-			// - Ergonomic benefits and health advantages
-			// - Original MSRP vs auction price (value proposition)
-			// - Sustainability impact (years of continued use vs landfill)
-			// - Condition assessment with specific wear patterns
-			CategoryID:  uuid.New(),
-			Condition:   string(ConditionGood),
-			Images:      []string{"aeron-chair-1.jpg", "aeron-chair-2.jpg"},
-			Location:    "Seattle, WA",
-			BuyNowPrice: ptr(350.0),
-			CreatedAt:   time.Now().Add(-24 * time.Hour),
-		},
-	}
-
-	return &GetItemsResponse{
+	if req.Condition != "" && !isValidCondition(req.Condition) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid condition").Err()
+	}
+	if req.Status != "" && !isValidStatus(req.Status) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid status").Err()
+	}
+	if req.MinPrice < 0 || req.MaxPrice < 0 {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("price filters must not be negative").Err()
+	}
+	if req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("min_price must not exceed max_price").Err()
+	}
+
+	page, limit := normalizePage(req.Page, req.Limit)
+	where, args := buildItemFilters(req)
+	orderBy := " ORDER BY i.created_at DESC, i.id"
+
+	var highlights map[string]*SearchHighlight
+	if text := strings.TrimSpace(req.Search); text != "" {
+		hits, err := searchIndex.Search(ctx, text, maxSearchHits)
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			return &GetItemsResponse{Items: []*Item{}, Page: page, Limit: limit}, nil
+		}
+
+		ids := make([]string, len(hits))
+		highlights = make(map[string]*SearchHighlight, len(hits))
+		for i, hit := range hits {
+			ids[i] = hit.ItemID.String()
+			highlights[ids[i]] = hit.Highlight
+		}
+
+		// Restrict to the hits and keep the index's relevance order
+		args = append(args, ids)
+		cond := fmt.Sprintf("i.id = ANY($%d::uuid[])", len(args))
+		where += " AND " + cond
+		orderBy = fmt.Sprintf(" ORDER BY array_position($%d::uuid[], i.id)", len(args))
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM items i" + where
+	if err := db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count items: %w", err)
+	}
+
+	query := "SELECT " + itemColumns + " FROM items i" + where +
+		orderBy + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := db.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("query items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate items: %w", err)
+	}
+
+	resp := &GetItemsResponse{
 		Items: items,
-		Total: len(items),
-	}, nil
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+	if highlights != nil {
+		resp.Highlights = make(map[string]*SearchHighlight, len(items))
+		for _, item := range items {
+			resp.Highlights[item.ID.String()] = highlights[item.ID.String()]
+		}
+	}
+	return resp, nil
 }
 
 //encore:api public method=GET path=/v1/items/:id
-func GetItem(ctx context.Context, id string) (*Item, error) {
+func GetItem(ctx context.Context, id string, req *GetItemRequest) (*Item, error) {
 	// AI-CHAT: Single item detail endpoint
 	// Returns comprehensive item information including:
 	// - High-resolution images with zoom capability
@@ -99,23 +178,53 @@ func GetItem(ctx context.Context, id string) (*Item, error) {
 	// - Environmental impact metrics
 	// - Similar items and price comparisons
 	// - Pickup/delivery options and scheduling
+	// Accepts either the item UUID or its slug so SEO URLs resolve directly
 
-	// TODO: Fetch from database by ID
 	// TODO: Include related auction information
-	// TODO: Log item view for analytics
-
-	return &Item{
-		ID:          uuid.MustParse(id),
-		Slug:        "herman-miller-aeron-chair",
-		Title:       "Herman Miller Aeron Chair",
-		Description: "Classic ergonomic office chair in good condition.",
-		CategoryID:  uuid.New(),
-		Condition:   string(ConditionGood),
-		Images:      []string{"aeron-chair-1.jpg"},
-		Location:    "Seattle, WA",
-		BuyNowPrice: ptr(350.0),
-		CreatedAt:   time.Now(),
-	}, nil
+
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("item id or slug is required").Err()
+	}
+
+	const slugQuery = "SELECT " + itemColumns + " FROM items i WHERE i.slug = $1"
+	query := slugQuery
+	var arg interface{} = id
+	if itemID, err := uuid.Parse(id); err == nil {
+		query = "SELECT " + itemColumns + " FROM items i WHERE i.id = $1"
+		arg = itemID
+	}
+
+	item, err := scanItem(db.QueryRow(ctx, query, arg))
+	if errors.Is(err, sqldb.ErrNoRows) && query == slugQuery {
+		// Fall back to retired slugs; callers compare item.Slug with the
+		// requested slug and redirect to the current URL when they differ.
+		item, err = scanItem(db.QueryRow(ctx, "SELECT "+itemColumns+`
+			FROM items i JOIN item_slug_history h ON h.item_id = i.id
+			WHERE h.slug = $1`, id))
+	}
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %q not found", id).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+
+	if item.Photos, err = loadItemImages(ctx, item.ID); err != nil {
+		return nil, err
+	}
+	if item.Lot, err = loadLotByItem(ctx, db, item.ID); err != nil {
+		return nil, err
+	}
+	if item.ConditionReport, err = loadLatestConditionReport(ctx, item.ID); err != nil {
+		return nil, err
+	}
+	if req.isPageView() {
+		recordItemView(ctx, item.ID, req.SessionID, req.UserID)
+		if item.ViewCount, err = countItemViews(ctx, item.ID); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
 }
 
 //encore:api public method=POST path=/v1/items
@@ -129,8 +238,7 @@ func CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
 
 	item := &Item{
 		ID:          uuid.New(),
-		Slug:        generateSlug(req.Title),
-		Title:       req.Title,
+		Title:       strings.TrimSpace(req.Title),
 		Description: req.Description,
 		CategoryID:  req.CategoryID,
 		Condition:   req.Condition,
@@ -139,41 +247,282 @@ func CreateItem(ctx context.Context, req *CreateItemRequest) (*Item, error) {
 		Dimensions:  req.Dimensions,
 		Weight:      req.Weight,
 		BuyNowPrice: req.BuyNowPrice,
+		Quantity:    req.Quantity,
+		Status:      req.Status,
 		CreatedBy:   req.CreatedBy,
-		CreatedAt:   time.Now(),
+	}
+	if item.Images == nil {
+		item.Images = []string{}
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	if item.Status == "" {
+		item.Status = string(StatusIntake)
+	}
+	if item.Status != string(StatusIntake) && item.Status != string(StatusListed) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("new items must start as intake or listed").Err()
+	}
+	if err := validateItem(item); err != nil {
+		return nil, err
+	}
+	decision, err := screenItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyScreening(ctx, item, decision); err != nil {
+		return nil, err
+	}
+
+	// The slug is checked up front, but a concurrent insert can still claim
+	// it first; retry a few times before giving up.
+	for attempt := 0; ; attempt++ {
+		err = insertScreenedItem(ctx, item, decision)
+		if sqldb.ErrCode(err) == sqlerr.UniqueViolation && attempt < maxSlugAttempts {
+			continue
+		}
+		break
+	}
+	if err != nil {
+		switch sqldb.ErrCode(err) {
+		case sqlerr.UniqueViolation:
+			return nil, errs.B().Code(errs.AlreadyExists).Msgf("could not allocate a unique slug for %q", item.Title).Err()
+		case sqlerr.ForeignKeyViolation:
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("category_id or created_by does not exist").Err()
+		}
+		return nil, fmt.Errorf("insert item: %w", err)
+	}
+
+	if err := reindexItem(ctx, item); err != nil {
+		// The item is saved; a later reindex will pick it up
+		rlog.Error("failed to index new item", "item_id", item.ID, "err", err)
 	}
 
-	// TODO: Insert into database
-	// TODO: Index in Meilisearch
 	// TODO: Log creation in audit log
 
 	return item, nil
 }
 
-//encore:api public method=GET path=/v1/categories
-func GetCategories(ctx context.Context) (*GetCategoriesResponse, error) {
-	// AI-CHAT: Returns all item categories
-	// Categories help users find specific types of office furniture
-	// Each category includes item count and featured items
+// Helper functions
+
+// itemColumns lists the items columns in the order scanItem expects them.
+// Queries must alias the items table as "i".
+const itemColumns = `i.id, i.slug, i.title, COALESCE(i.description, ''), i.category_id,
+	COALESCE(i.condition, ''), COALESCE(i.images, '[]'::jsonb), COALESCE(i.location, ''),
+	i.dimensions, i.weight, i.buy_now_price, i.status, i.created_by, i.created_at,
+	i.updated_at, i.deleted_at, i.lot_id, i.quantity, i.bin_id, i.sku, i.flagged_for_review,
+	i.watcher_count`
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	maxSlugAttempts = 3
+)
+
+// querier is satisfied by both *sqldb.Database and *sqldb.Tx.
+type querier interface {
+	Exec(ctx context.Context, query string, args ...interface{}) (sqldb.ExecResult, error)
+	Query(ctx context.Context, query string, args ...interface{}) (*sqldb.Rows, error)
+	QueryRow(ctx context.Context, query string, args ...interface{}) *sqldb.Row
+}
+
+// insertScreenedItem inserts a new item together with its screening
+// decision, so a flagged item is never saved without the audit record that
+// explains why.
+func insertScreenedItem(ctx context.Context, item *Item, decision *screening.Decision) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	categories := []*Category{
-		{ID: uuid.New(), Name: "Office Chairs", Slug: "office-chairs"},
-		{ID: uuid.New(), Name: "Desks", Slug: "desks"},
-		{ID: uuid.New(), Name: "Electronics", Slug: "electronics"},
-		{ID: uuid.New(), Name: "Storage & Shelving", Slug: "storage-shelving"},
-		{ID: uuid.New(), Name: "Mystery Pallets", Slug: "mystery-pallets"},
-		{ID: uuid.New(), Name: "Free Pickup Finds", Slug: "free-pickup"},
+	if err := insertItem(ctx, tx, item); err != nil {
+		return err
+	}
+	if err := recordScreening(ctx, tx, item, decision); err != nil {
+		return fmt.Errorf("record screening decision: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit item: %w", err)
 	}
+	return nil
+}
+
+// insertItem allocates a unique slug for a validated item and inserts it,
+// filling in the item's Slug, SKU and CreatedAt.
+func insertItem(ctx context.Context, q querier, item *Item) error {
+	images, dimensions, err := encodeItemJSON(item)
+	if err != nil {
+		return err
+	}
+	if item.Slug, err = uniqueSlug(ctx, q, generateSlug(item.Title), item.ID); err != nil {
+		return err
+	}
+	return q.QueryRow(ctx, `
+		INSERT INTO items (id, slug, title, description, category_id, condition, images,
+			location, dimensions, weight, buy_now_price, status, created_by, quantity, flagged_for_review)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING created_at, sku
+	`, item.ID, item.Slug, item.Title, item.Description, nullUUID(item.CategoryID),
+		item.Condition, images, item.Location, dimensions, item.Weight, item.BuyNowPrice,
+		item.Status, nullUUID(item.CreatedBy), item.Quantity, item.FlaggedForReview).Scan(&item.CreatedAt, &item.SKU)
+}
 
-	return &GetCategoriesResponse{
-		Categories: categories,
-	}, nil
+// rowScanner is satisfied by both *sqldb.Row and *sqldb.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// Helper functions
-func generateSlug(title string) string {
-	// TODO: Implement proper slug generation
-	return "generated-slug"
+func scanItem(row rowScanner) (*Item, error) {
+	var (
+		item       Item
+		categoryID *uuid.UUID
+		createdBy  *uuid.UUID
+		images     []byte
+		dimensions []byte
+	)
+	err := row.Scan(&item.ID, &item.Slug, &item.Title, &item.Description, &categoryID,
+		&item.Condition, &images, &item.Location, &dimensions, &item.Weight,
+		&item.BuyNowPrice, &item.Status, &createdBy, &item.CreatedAt, &item.UpdatedAt,
+		&item.DeletedAt, &item.LotID, &item.Quantity, &item.BinID, &item.SKU, &item.FlaggedForReview,
+		&item.WatcherCount)
+	if err != nil {
+		return nil, err
+	}
+	if categoryID != nil {
+		item.CategoryID = *categoryID
+	}
+	if createdBy != nil {
+		item.CreatedBy = *createdBy
+	}
+	if err := json.Unmarshal(images, &item.Images); err != nil {
+		return nil, fmt.Errorf("decode images for item %s: %w", item.ID, err)
+	}
+	if len(dimensions) > 0 {
+		item.Dimensions = &Dimensions{}
+		if err := json.Unmarshal(dimensions, item.Dimensions); err != nil {
+			return nil, fmt.Errorf("decode dimensions for item %s: %w", item.ID, err)
+		}
+	}
+	return &item, nil
+}
+
+// buildItemFilters translates the request filters into a WHERE clause and
+// its positional arguments. Soft-deleted items are always excluded, as are
+// items grouped into a lot, which are browsed through the lot's listing.
+// Queries must alias items as "i".
+func buildItemFilters(req *GetItemsRequest) (string, []interface{}) {
+	var (
+		conds = []string{"i.deleted_at IS NULL", "i.lot_id IS NULL"}
+		args  []interface{}
+	)
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if req.Category != "" {
+		// Browsing a category includes everything in its subcategories
+		add(`i.category_id IN (
+			WITH RECURSIVE sub AS (
+				SELECT id FROM categories WHERE slug = $%d
+				UNION ALL
+				SELECT c2.id FROM categories c2 JOIN sub ON c2.parent_id = sub.id
+			)
+			SELECT id FROM sub
+		)`, req.Category)
+	}
+	if req.Condition != "" {
+		add("i.condition = $%d", req.Condition)
+	}
+	if req.Status != "" {
+		add("i.status = $%d", req.Status)
+	}
+	if req.MinPrice > 0 {
+		add("i.buy_now_price >= $%d", req.MinPrice)
+	}
+	if req.MaxPrice > 0 {
+		add("i.buy_now_price <= $%d", req.MaxPrice)
+	}
+	if loc := strings.TrimSpace(req.Location); loc != "" {
+		add("i.location ILIKE '%%' || $%d || '%%'", escapeLike(loc))
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// normalizePage applies defaults and bounds to 1-based pagination params.
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func isValidCondition(c string) bool {
+	switch ItemCondition(c) {
+	case ConditionNew, ConditionLikeNew, ConditionGood, ConditionFair, ConditionParts:
+		return true
+	}
+	return false
+}
+
+// validateItem checks the user-editable fields of an item before it is
+// written.
+func validateItem(item *Item) error {
+	switch {
+	case strings.TrimSpace(item.Title) == "":
+		return errs.B().Code(errs.InvalidArgument).Msg("title is required").Err()
+	case !isValidCondition(item.Condition):
+		return errs.B().Code(errs.InvalidArgument).Msg("condition must be one of new, like_new, good, fair, parts").Err()
+	case item.Weight != nil && *item.Weight < 0:
+		return errs.B().Code(errs.InvalidArgument).Msg("weight must not be negative").Err()
+	case item.BuyNowPrice != nil && *item.BuyNowPrice < 0:
+		return errs.B().Code(errs.InvalidArgument).Msg("buy_now_price must not be negative").Err()
+	case item.Quantity < 0:
+		return errs.B().Code(errs.InvalidArgument).Msg("quantity must not be negative").Err()
+	}
+	if d := item.Dimensions; d != nil {
+		if d.Units != "in" && d.Units != "cm" {
+			return errs.B().Code(errs.InvalidArgument).Msg(`dimensions.units must be "in" or "cm"`).Err()
+		}
+		if d.Width < 0 || d.Height < 0 || d.Depth < 0 {
+			return errs.B().Code(errs.InvalidArgument).Msg("dimensions must not be negative").Err()
+		}
+	}
+	return nil
+}
+
+// encodeItemJSON encodes the JSONB columns of an item. Dimensions are nil
+// when unset so they're stored as SQL NULL.
+func encodeItemJSON(item *Item) (images, dimensions []byte, err error) {
+	if images, err = json.Marshal(item.Images); err != nil {
+		return nil, nil, fmt.Errorf("encode images: %w", err)
+	}
+	if item.Dimensions != nil {
+		if dimensions, err = json.Marshal(item.Dimensions); err != nil {
+			return nil, nil, fmt.Errorf("encode dimensions: %w", err)
+		}
+	}
+	return images, dimensions, nil
+}
+
+// nullUUID maps the zero UUID to SQL NULL for optional foreign keys.
+func nullUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
 }
 
 func ptr[T any](v T) *T {
@@ -187,6 +536,7 @@ type GetItemsRequest struct {
 	MinPrice  float64 `query:"min_price"`
 	MaxPrice  float64 `query:"max_price"`
 	Location  string  `query:"location"`
+	Status    string  `query:"status"`
 	Search    string  `query:"search"`
 	Page      int     `query:"page"`
 	Limit     int     `query:"limit"`
@@ -195,6 +545,10 @@ type GetItemsRequest struct {
 type GetItemsResponse struct {
 	Items []*Item `json:"items"`
 	Total int     `json:"total"`
+	Page  int     `json:"page"`
+	Limit int     `json:"limit"`
+	// Highlights maps item IDs to search snippets when a search term is given
+	Highlights map[string]*SearchHighlight `json:"highlights,omitempty"`
 }
 
 type CreateItemRequest struct {
@@ -207,9 +561,7 @@ type CreateItemRequest struct {
 	Dimensions  *Dimensions `json:"dimensions,omitempty"`
 	Weight      *float64    `json:"weight,omitempty"`
 	BuyNowPrice *float64    `json:"buy_now_price,omitempty"`
+	Quantity    int         `json:"quantity,omitempty"` // Defaults to 1
+	Status      string      `json:"status,omitempty"`   // "intake" (default) or "listed"
 	CreatedBy   uuid.UUID   `json:"created_by"`
 }
-
-type GetCategoriesResponse struct {
-	Categories []*Category `json:"categories"`
-}
\ No newline at end of file
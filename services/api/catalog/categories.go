@@ -0,0 +1,272 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+)
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+//encore:api public method=GET path=/v1/categories
+func GetCategories(ctx context.Context) (*GetCategoriesResponse, error) {
+	// AI-CHAT: Returns the category tree
+	// Categories help users find specific types of office furniture
+	// Each category includes its own and its subtree's live item counts
+
+	all, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := []*Category{}
+	for _, c := range all {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		}
+	}
+	return &GetCategoriesResponse{
+		Categories: roots,
+	}, nil
+}
+
+//encore:api public method=GET path=/v1/categories/:id
+func GetCategory(ctx context.Context, id string) (*Category, error) {
+	// AI-CHAT: Single category with its subcategories, by ID or slug
+	all, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findCategory(all, id)
+}
+
+//encore:api public method=POST path=/v1/categories
+func CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*Category, error) {
+	// AI-CHAT: Admin endpoint for adding categories and subcategories
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("name is required").Err()
+	}
+	slug := req.Slug
+	if slug == "" {
+		slug = generateSlug(name)
+	} else if !categorySlugPattern.MatchString(slug) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("slug must be lower-case letters, digits and hyphens").Err()
+	}
+
+	category := &Category{
+		ID:       uuid.New(),
+		Name:     name,
+		Slug:     slug,
+		ParentID: req.ParentID,
+	}
+	_, err := db.Exec(ctx, `
+		INSERT INTO categories (id, name, slug, parent_id)
+		VALUES ($1, $2, $3, $4)
+	`, category.ID, category.Name, category.Slug, category.ParentID)
+	if err != nil {
+		return nil, categoryWriteError(err)
+	}
+	return category, nil
+}
+
+//encore:api public method=PATCH path=/v1/categories/:id
+func UpdateCategory(ctx context.Context, id string, req *UpdateCategoryRequest) (*Category, error) {
+	// AI-CHAT: Rename, re-slug or move a category within the hierarchy
+	all, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	category, err := findCategory(all, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("name must not be empty").Err()
+		}
+		category.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Slug != nil {
+		if !categorySlugPattern.MatchString(*req.Slug) {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("slug must be lower-case letters, digits and hyphens").Err()
+		}
+		category.Slug = *req.Slug
+	}
+	if req.ClearParent {
+		category.ParentID = nil
+	} else if req.ParentID != nil {
+		// Moving a category under itself or one of its descendants would
+		// create a cycle
+		if isInSubtree(category, *req.ParentID) {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("a category cannot be moved under itself or its subcategories").Err()
+		}
+		category.ParentID = req.ParentID
+	}
+
+	_, err = db.Exec(ctx, `
+		UPDATE categories SET name = $2, slug = $3, parent_id = $4
+		WHERE id = $1
+	`, category.ID, category.Name, category.Slug, category.ParentID)
+	if err != nil {
+		return nil, categoryWriteError(err)
+	}
+	return category, nil
+}
+
+//encore:api public method=DELETE path=/v1/categories/:id
+func DeleteCategory(ctx context.Context, id string) error {
+	// AI-CHAT: Removes an empty category
+	// Categories still referenced by items or with subcategories are kept so
+	// listings never point at a missing category
+	all, err := loadCategories(ctx)
+	if err != nil {
+		return err
+	}
+	category, err := findCategory(all, id)
+	if err != nil {
+		return err
+	}
+	if len(category.Children) > 0 {
+		return errs.B().Code(errs.FailedPrecondition).Msgf("category %q has subcategories", category.Slug).Err()
+	}
+
+	// Check and delete in one statement so an item assigned concurrently
+	// can't slip in between
+	result, err := db.Exec(ctx, `
+		DELETE FROM categories c
+		WHERE c.id = $1 AND NOT EXISTS (SELECT 1 FROM items i WHERE i.category_id = c.id)
+	`, category.ID)
+	if err != nil {
+		if sqldb.ErrCode(err) == sqlerr.ForeignKeyViolation {
+			return errs.B().Code(errs.FailedPrecondition).Msgf("category %q is still in use", category.Slug).Err()
+		}
+		return fmt.Errorf("delete category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errs.B().Code(errs.FailedPrecondition).Msgf("category %q is still referenced by items", category.Slug).Err()
+	}
+	return nil
+}
+
+// loadCategories returns every category ordered by name, with children and
+// item counts populated. The category table is small enough to load whole.
+func loadCategories(ctx context.Context) ([]*Category, error) {
+	rows, err := db.Query(ctx, `
+		SELECT c.id, c.name, c.slug, c.parent_id, COUNT(i.id)
+		FROM categories c
+		LEFT JOIN items i ON i.category_id = c.id AND i.deleted_at IS NULL
+		GROUP BY c.id
+		ORDER BY c.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var ordered []*Category
+	for rows.Next() {
+		c := &Category{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.Slug, &c.ParentID, &c.ItemCount); err != nil {
+			return nil, fmt.Errorf("scan category: %w", err)
+		}
+		ordered = append(ordered, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate categories: %w", err)
+	}
+
+	buildCategoryTree(ordered)
+	return ordered, nil
+}
+
+// buildCategoryTree links each category to its parent's Children, keeping
+// the given order, and rolls item counts up from the leaves.
+func buildCategoryTree(ordered []*Category) {
+	byID := make(map[uuid.UUID]*Category, len(ordered))
+	for _, c := range ordered {
+		byID[c.ID] = c
+	}
+	for _, c := range ordered {
+		if c.ParentID == nil {
+			continue
+		}
+		if parent, ok := byID[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, c)
+		}
+	}
+	for _, c := range ordered {
+		if c.ParentID == nil {
+			sumItemCounts(c)
+		}
+	}
+}
+
+// sumItemCounts fills TotalItemCount for c and its subtree.
+func sumItemCounts(c *Category) int {
+	c.TotalItemCount = c.ItemCount
+	for _, child := range c.Children {
+		c.TotalItemCount += sumItemCounts(child)
+	}
+	return c.TotalItemCount
+}
+
+// isInSubtree reports whether id is root or one of its descendants.
+func isInSubtree(root *Category, id uuid.UUID) bool {
+	if root.ID == id {
+		return true
+	}
+	for _, child := range root.Children {
+		if isInSubtree(child, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// findCategory looks a category up by UUID or slug.
+func findCategory(all []*Category, idOrSlug string) (*Category, error) {
+	id, err := uuid.Parse(idOrSlug)
+	for _, c := range all {
+		if (err == nil && c.ID == id) || c.Slug == idOrSlug {
+			return c, nil
+		}
+	}
+	return nil, errs.B().Code(errs.NotFound).Msgf("category %q not found", idOrSlug).Err()
+}
+
+func categoryWriteError(err error) error {
+	switch sqldb.ErrCode(err) {
+	case sqlerr.UniqueViolation:
+		return errs.B().Code(errs.AlreadyExists).Msg("a category with that name or slug already exists").Err()
+	case sqlerr.ForeignKeyViolation:
+		return errs.B().Code(errs.InvalidArgument).Msg("parent category does not exist").Err()
+	case sqlerr.CheckViolation:
+		return errs.B().Code(errs.InvalidArgument).Msg("a category cannot be its own parent").Err()
+	}
+	return fmt.Errorf("write category: %w", err)
+}
+
+type GetCategoriesResponse struct {
+	Categories []*Category `json:"categories"`
+}
+
+type CreateCategoryRequest struct {
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug,omitempty"` // Derived from name when empty
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+type UpdateCategoryRequest struct {
+	Name        *string    `json:"name,omitempty"`
+	Slug        *string    `json:"slug,omitempty"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	ClearParent bool       `json:"clear_parent,omitempty"` // Move to the top level
+}
@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// categoryTree builds furniture > seating > office-chairs, plus a separate
+// lighting root, with item counts at each level.
+func categoryTree() (furniture, seating, chairs, lighting *Category) {
+	furniture = &Category{ID: uuid.New(), Slug: "furniture", ItemCount: 2}
+	seating = &Category{ID: uuid.New(), Slug: "seating", ParentID: &furniture.ID, ItemCount: 3}
+	chairs = &Category{ID: uuid.New(), Slug: "office-chairs", ParentID: &seating.ID, ItemCount: 5}
+	lighting = &Category{ID: uuid.New(), Slug: "lighting", ItemCount: 1}
+	buildCategoryTree([]*Category{chairs, furniture, lighting, seating})
+	return furniture, seating, chairs, lighting
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	furniture, seating, chairs, lighting := categoryTree()
+
+	if len(furniture.Children) != 1 || furniture.Children[0] != seating {
+		t.Errorf("Expected seating under furniture, got %v", furniture.Children)
+	}
+	if len(seating.Children) != 1 || seating.Children[0] != chairs {
+		t.Errorf("Expected office-chairs under seating, got %v", seating.Children)
+	}
+	if len(chairs.Children) != 0 || len(lighting.Children) != 0 {
+		t.Error("Expected leaf categories to have no children")
+	}
+}
+
+func TestBuildCategoryTreeOrphan(t *testing.T) {
+	missing := uuid.New()
+	orphan := &Category{ID: uuid.New(), Slug: "orphan", ParentID: &missing, ItemCount: 4}
+	buildCategoryTree([]*Category{orphan})
+
+	// Not reachable from a root, so nothing rolls its count up
+	if orphan.TotalItemCount != 0 {
+		t.Errorf("Expected an orphan's total to be left unset, got %d", orphan.TotalItemCount)
+	}
+}
+
+func TestSumItemCounts(t *testing.T) {
+	furniture, seating, chairs, lighting := categoryTree()
+
+	testCases := []struct {
+		category *Category
+		expected int
+	}{
+		{furniture, 10},
+		{seating, 8},
+		{chairs, 5},
+		{lighting, 1},
+	}
+
+	for _, tc := range testCases {
+		if tc.category.TotalItemCount != tc.expected {
+			t.Errorf("%s: expected a total of %d, got %d", tc.category.Slug, tc.expected, tc.category.TotalItemCount)
+		}
+	}
+
+	// Recounting after an item lands deeper in the tree
+	chairs.ItemCount++
+	if got := sumItemCounts(furniture); got != 11 {
+		t.Errorf("Expected furniture's total to be 11 after recounting, got %d", got)
+	}
+}
+
+func TestIsInSubtree(t *testing.T) {
+	furniture, seating, chairs, lighting := categoryTree()
+
+	testCases := []struct {
+		name     string
+		root     *Category
+		id       uuid.UUID
+		expected bool
+	}{
+		{"itself", seating, seating.ID, true},
+		{"child", furniture, seating.ID, true},
+		{"grandchild", furniture, chairs.ID, true},
+		{"parent", seating, furniture.ID, false},
+		{"sibling tree", furniture, lighting.ID, false},
+		{"leaf under unrelated root", lighting, chairs.ID, false},
+		{"unknown", furniture, uuid.New(), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isInSubtree(tc.root, tc.id); got != tc.expected {
+				t.Errorf("isInSubtree(%s, %s) = %v, expected %v", tc.root.Slug, tc.id, got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// ConditionReport is a grader's structured inspection of an item. The grade
+// is computed from the checklist results and becomes the item's Condition.
+type ConditionReport struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	ItemID    uuid.UUID         `json:"item_id" db:"item_id"`
+	GraderID  uuid.UUID         `json:"grader_id" db:"grader_id"`
+	Checklist string            `json:"checklist" db:"checklist"`
+	Checks    []*ConditionCheck `json:"checks" db:"checks"`
+	Unused    bool              `json:"unused" db:"unused"` // Never used, e.g. still in the box
+	Notes     string            `json:"notes,omitempty" db:"notes"`
+	Grade     string            `json:"grade" db:"grade"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// ConditionCheck is the result for one checklist point. Defects can point at
+// the item images that show them.
+type ConditionCheck struct {
+	Key      string      `json:"key"`
+	Rating   string      `json:"rating"`
+	Note     string      `json:"note,omitempty"`
+	ImageIDs []uuid.UUID `json:"image_ids,omitempty"`
+}
+
+// CheckRating grades a single checklist point
+type CheckRating string
+
+const (
+	RatingOK            CheckRating = "ok"
+	RatingMinor         CheckRating = "minor"   // Cosmetic wear that doesn't affect use
+	RatingMajor         CheckRating = "major"   // Damaged or needs repair
+	RatingMissing       CheckRating = "missing" // Part absent
+	RatingNotApplicable CheckRating = "na"      // e.g. a chair sold without arms
+)
+
+// Checklist is the set of points graders inspect for a kind of item.
+type Checklist struct {
+	Name   string            `json:"name"`
+	Points []*ChecklistPoint `json:"points"`
+}
+
+// ChecklistPoint is one thing to inspect. A major defect on a critical point
+// means the item is only good for parts.
+type ChecklistPoint struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Critical bool   `json:"critical"`
+}
+
+// conditionChecklists maps category slugs to their checklist. Subcategories
+// use the nearest ancestor's checklist, and anything else the general one.
+var conditionChecklists = map[string]*Checklist{
+	"office-chairs": {Name: "office-chairs", Points: []*ChecklistPoint{
+		{Key: "casters", Label: "Casters roll and lock"},
+		{Key: "gas_lift", Label: "Gas lift holds height", Critical: true},
+		{Key: "mesh", Label: "Mesh or upholstery"},
+		{Key: "arms", Label: "Arms and armpads"},
+		{Key: "base", Label: "Base and star", Critical: true},
+		{Key: "tilt", Label: "Tilt and recline controls"},
+	}},
+	"desks": {Name: "desks", Points: []*ChecklistPoint{
+		{Key: "surface", Label: "Work surface"},
+		{Key: "frame", Label: "Legs and frame", Critical: true},
+		{Key: "drawers", Label: "Drawers and locks"},
+		{Key: "height_adjust", Label: "Height adjustment"},
+	}},
+	"electronics": {Name: "electronics", Points: []*ChecklistPoint{
+		{Key: "powers_on", Label: "Powers on", Critical: true},
+		{Key: "display", Label: "Screen or display", Critical: true},
+		{Key: "ports", Label: "Ports and buttons"},
+		{Key: "accessories", Label: "Cables and accessories"},
+		{Key: "housing", Label: "Housing"},
+	}},
+	"storage-shelving": {Name: "storage-shelving", Points: []*ChecklistPoint{
+		{Key: "frame", Label: "Frame is square and stable", Critical: true},
+		{Key: "shelves", Label: "Shelves and drawers"},
+		{Key: "hardware", Label: "Hardware and keys"},
+		{Key: "finish", Label: "Finish"},
+	}},
+}
+
+var generalChecklist = &Checklist{Name: "general", Points: []*ChecklistPoint{
+	{Key: "structure", Label: "Structure", Critical: true},
+	{Key: "function", Label: "Works as intended", Critical: true},
+	{Key: "surfaces", Label: "Surfaces and finish"},
+	{Key: "completeness", Label: "All parts present"},
+}}
+
+// fairMinorDefects is the number of minor defects at which an item drops
+// from good to fair.
+const fairMinorDefects = 3
+
+//encore:api public method=GET path=/v1/items/:id/condition-checklist
+func GetConditionChecklist(ctx context.Context, id string) (*Checklist, error) {
+	// AI-CHAT: The checklist graders fill in for this item's category
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	var categoryID *uuid.UUID
+	err = db.QueryRow(ctx, "SELECT category_id FROM items WHERE id = $1 AND deleted_at IS NULL", itemID).Scan(&categoryID)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	return checklistForCategory(ctx, categoryID)
+}
+
+//encore:api public method=POST path=/v1/items/:id/condition-reports
+func CreateConditionReport(ctx context.Context, id string, req *CreateConditionReportRequest) (*ConditionReport, error) {
+	// AI-CHAT: Records a grader's inspection and regrades the item
+	// Every report is kept; the newest one sets the item's condition.
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var categoryID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT category_id FROM items WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, itemID).Scan(&categoryID)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+
+	var role string
+	err = tx.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", req.GraderID).Scan(&role)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("grader_id does not exist").Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load grader: %w", err)
+	}
+	if role == "bidder" {
+		return nil, errs.B().Code(errs.PermissionDenied).Msg("only staff and volunteers can grade items").Err()
+	}
+
+	checklist, err := checklistForCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConditionChecks(checklist, req.Checks); err != nil {
+		return nil, err
+	}
+	if err := checkDefectImages(ctx, tx, itemID, req.Checks); err != nil {
+		return nil, err
+	}
+
+	report := &ConditionReport{
+		ID:        uuid.New(),
+		ItemID:    itemID,
+		GraderID:  req.GraderID,
+		Checklist: checklist.Name,
+		Checks:    req.Checks,
+		Unused:    req.Unused,
+		Notes:     req.Notes,
+		Grade:     string(computeConditionGrade(checklist, req.Checks, req.Unused)),
+	}
+	checks, err := json.Marshal(report.Checks)
+	if err != nil {
+		return nil, fmt.Errorf("encode checks: %w", err)
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO condition_reports (id, item_id, grader_id, checklist, checks, unused, notes, grade)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`, report.ID, report.ItemID, report.GraderID, report.Checklist, checks, report.Unused,
+		report.Notes, report.Grade).Scan(&report.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert condition report: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE items SET condition = $2, updated_at = NOW() WHERE id = $1
+	`, itemID, report.Grade)
+	if err != nil {
+		return nil, fmt.Errorf("update item condition: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit condition report: %w", err)
+	}
+	return report, nil
+}
+
+//encore:api public method=GET path=/v1/items/:id/condition-reports
+func ListConditionReports(ctx context.Context, id string) (*ListConditionReportsResponse, error) {
+	// AI-CHAT: Grading history for an item, newest first
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	rows, err := db.Query(ctx, "SELECT "+conditionReportColumns+`
+		FROM condition_reports r
+		WHERE r.item_id = $1
+		ORDER BY r.created_at DESC
+	`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("query condition reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*ConditionReport{}
+	for rows.Next() {
+		report, err := scanConditionReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan condition report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate condition reports: %w", err)
+	}
+	return &ListConditionReportsResponse{Reports: reports}, nil
+}
+
+// conditionReportColumns lists the condition_reports columns in the order
+// scanConditionReport expects them. Queries must alias the table as "r".
+const conditionReportColumns = `r.id, r.item_id, r.grader_id, r.checklist, r.checks,
+	r.unused, COALESCE(r.notes, ''), r.grade, r.created_at`
+
+func scanConditionReport(row rowScanner) (*ConditionReport, error) {
+	report := &ConditionReport{}
+	var checks []byte
+	err := row.Scan(&report.ID, &report.ItemID, &report.GraderID, &report.Checklist, &checks,
+		&report.Unused, &report.Notes, &report.Grade, &report.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(checks, &report.Checks); err != nil {
+		return nil, fmt.Errorf("decode checks of report %s: %w", report.ID, err)
+	}
+	return report, nil
+}
+
+// loadLatestConditionReport returns the newest condition report for an item,
+// or nil if it has never been graded.
+func loadLatestConditionReport(ctx context.Context, itemID uuid.UUID) (*ConditionReport, error) {
+	report, err := scanConditionReport(db.QueryRow(ctx, "SELECT "+conditionReportColumns+`
+		FROM condition_reports r
+		WHERE r.item_id = $1
+		ORDER BY r.created_at DESC
+		LIMIT 1
+	`, itemID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("load condition report: %w", err)
+	}
+	return report, nil
+}
+
+// checklistForCategory returns the checklist of the category or its nearest
+// ancestor that has one, falling back to the general checklist.
+func checklistForCategory(ctx context.Context, categoryID *uuid.UUID) (*Checklist, error) {
+	if categoryID == nil {
+		return generalChecklist, nil
+	}
+	all, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*Category, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+	// The depth bound guards against a cycle slipping into the hierarchy
+	for c, depth := byID[*categoryID], 0; c != nil && depth < len(all); depth++ {
+		if checklist, ok := conditionChecklists[c.Slug]; ok {
+			return checklist, nil
+		}
+		if c.ParentID == nil {
+			break
+		}
+		c = byID[*c.ParentID]
+	}
+	return generalChecklist, nil
+}
+
+// validateConditionChecks requires exactly one valid result for every point
+// on the checklist.
+func validateConditionChecks(checklist *Checklist, checks []*ConditionCheck) error {
+	seen := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		if check == nil || checklistPoint(checklist, check.Key) == nil {
+			return errs.B().Code(errs.InvalidArgument).Msgf("%s is not on the %s checklist", checkKey(check), checklist.Name).Err()
+		}
+		if seen[check.Key] {
+			return errs.B().Code(errs.InvalidArgument).Msgf("%s is checked more than once", check.Key).Err()
+		}
+		seen[check.Key] = true
+		switch CheckRating(check.Rating) {
+		case RatingOK, RatingMinor, RatingMajor, RatingMissing, RatingNotApplicable:
+		default:
+			return errs.B().Code(errs.InvalidArgument).Msgf("%s rating must be one of ok, minor, major, missing, na", check.Key).Err()
+		}
+	}
+	for _, point := range checklist.Points {
+		if !seen[point.Key] {
+			return errs.B().Code(errs.InvalidArgument).Msgf("%s has not been checked", point.Key).Err()
+		}
+	}
+	return nil
+}
+
+// checkDefectImages ensures defect photos are images of this item.
+func checkDefectImages(ctx context.Context, q querier, itemID uuid.UUID, checks []*ConditionCheck) error {
+	var imageIDs []uuid.UUID
+	for _, check := range checks {
+		imageIDs = append(imageIDs, check.ImageIDs...)
+	}
+	if len(imageIDs) == 0 {
+		return nil
+	}
+	var missing int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM unnest($2::uuid[]) AS ids(id)
+		WHERE NOT EXISTS (SELECT 1 FROM item_images img WHERE img.id = ids.id AND img.item_id = $1)
+	`, itemID, imageIDs).Scan(&missing)
+	if err != nil {
+		return fmt.Errorf("check defect images: %w", err)
+	}
+	if missing > 0 {
+		return errs.B().Code(errs.InvalidArgument).Msg("image_ids must refer to images of this item").Err()
+	}
+	return nil
+}
+
+// computeConditionGrade derives the item condition from checklist results:
+// a major defect or missing part on a critical point means parts only, any
+// other major defect or several minor ones mean fair, a little wear is good,
+// and a flawless item is like new, or new if it was never used.
+func computeConditionGrade(checklist *Checklist, checks []*ConditionCheck, unused bool) ItemCondition {
+	var minor, major int
+	for _, check := range checks {
+		switch CheckRating(check.Rating) {
+		case RatingMinor:
+			minor++
+		case RatingMajor, RatingMissing:
+			if point := checklistPoint(checklist, check.Key); point != nil && point.Critical {
+				return ConditionParts
+			}
+			major++
+		}
+	}
+	switch {
+	case major > 0 || minor >= fairMinorDefects:
+		return ConditionFair
+	case minor > 0:
+		return ConditionGood
+	case unused:
+		return ConditionNew
+	}
+	return ConditionLikeNew
+}
+
+func checklistPoint(checklist *Checklist, key string) *ChecklistPoint {
+	for _, point := range checklist.Points {
+		if point.Key == key {
+			return point
+		}
+	}
+	return nil
+}
+
+func checkKey(check *ConditionCheck) string {
+	if check == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%q", check.Key)
+}
+
+// Request/Response types
+
+type CreateConditionReportRequest struct {
+	GraderID uuid.UUID         `json:"grader_id"`
+	Checks   []*ConditionCheck `json:"checks"`
+	Unused   bool              `json:"unused"`
+	Notes    string            `json:"notes,omitempty"`
+}
+
+type ListConditionReportsResponse struct {
+	Reports []*ConditionReport `json:"reports"`
+}
@@ -0,0 +1,57 @@
+package catalog
+
+import "testing"
+
+func TestComputeConditionGrade(t *testing.T) {
+	chair := conditionChecklists["office-chairs"]
+	checks := func(ratings map[string]CheckRating) []*ConditionCheck {
+		var out []*ConditionCheck
+		for _, point := range chair.Points {
+			rating := RatingOK
+			if r, ok := ratings[point.Key]; ok {
+				rating = r
+			}
+			out = append(out, &ConditionCheck{Key: point.Key, Rating: string(rating)})
+		}
+		return out
+	}
+
+	testCases := []struct {
+		name     string
+		ratings  map[string]CheckRating
+		unused   bool
+		expected ItemCondition
+	}{
+		{"flawless", nil, false, ConditionLikeNew},
+		{"flawless and unused", nil, true, ConditionNew},
+		{"worn mesh", map[string]CheckRating{"mesh": RatingMinor}, false, ConditionGood},
+		{"no arms", map[string]CheckRating{"arms": RatingNotApplicable}, false, ConditionLikeNew},
+		{"several scuffs", map[string]CheckRating{"mesh": RatingMinor, "arms": RatingMinor, "casters": RatingMinor}, false, ConditionFair},
+		{"missing caster", map[string]CheckRating{"casters": RatingMissing}, false, ConditionFair},
+		{"dead gas lift", map[string]CheckRating{"gas_lift": RatingMajor}, false, ConditionParts},
+		{"unused but cracked base", map[string]CheckRating{"base": RatingMajor}, true, ConditionParts},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := computeConditionGrade(chair, checks(tc.ratings), tc.unused); got != tc.expected {
+				t.Errorf("computeConditionGrade() = %s, expected %s", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestConditionChecklistsAreWellFormed(t *testing.T) {
+	for slug, checklist := range conditionChecklists {
+		if checklist.Name != slug {
+			t.Errorf("checklist for %s is named %s", slug, checklist.Name)
+		}
+		seen := map[string]bool{}
+		for _, point := range checklist.Points {
+			if seen[point.Key] {
+				t.Errorf("checklist %s repeats %s", slug, point.Key)
+			}
+			seen[point.Key] = true
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// imageVariantSpecs are the sizes generated for every uploaded image, largest
+// first. Each variant fits within MaxDim on its longest side and is never
+// upscaled.
+var imageVariantSpecs = []struct {
+	Name   string
+	MaxDim int
+}{
+	{"large", 1600},
+	{"medium", 800},
+	{"thumbnail", 200},
+}
+
+const (
+	// maxImagePixels guards against decompression bombs: a small file that
+	// decodes to an enormous bitmap.
+	maxImagePixels = 50_000_000
+	jpegQuality    = 85
+)
+
+// renderedVariant is an encoded image variant ready for upload.
+type renderedVariant struct {
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// renderImageVariants decodes an uploaded image and re-encodes it as JPEG at
+// every variant size. Re-encoding drops all metadata, so EXIF data including
+// GPS coordinates never reaches storage; the EXIF orientation is applied to
+// the pixels first so photos still display upright.
+func renderImageVariants(data []byte) (variants []*renderedVariant, width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("read image header: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, 0, 0, fmt.Errorf("image is %dx%d, larger than the %d pixel limit", cfg.Width, cfg.Height, maxImagePixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("decode image: %w", err)
+	}
+
+	// Scale to the largest variant before reorienting so the per-pixel
+	// rotation works on as few pixels as possible. Smaller variants are
+	// derived from the previous one.
+	orientation := exifOrientation(data)
+	current := applyOrientation(resizeToFit(src, imageVariantSpecs[0].MaxDim), orientation)
+	if orientation >= 5 {
+		width, height = cfg.Height, cfg.Width
+	} else {
+		width, height = cfg.Width, cfg.Height
+	}
+
+	for i, spec := range imageVariantSpecs {
+		if i > 0 {
+			current = resizeToFit(current, spec.MaxDim)
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, current, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, 0, 0, fmt.Errorf("encode %s variant: %w", spec.Name, err)
+		}
+		b := current.Bounds()
+		variants = append(variants, &renderedVariant{
+			Name:   spec.Name,
+			Data:   buf.Bytes(),
+			Width:  b.Dx(),
+			Height: b.Dy(),
+		})
+	}
+	return variants, width, height, nil
+}
+
+// resizeToFit scales src so its longest side is at most maxDim, flattening
+// any transparency onto white since variants are stored as JPEG.
+func resizeToFit(src image.Image, maxDim int) image.Image {
+	b := src.Bounds()
+	w, h := fitWithin(b.Dx(), b.Dy(), maxDim)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	if w == b.Dx() && h == b.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Over)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Over, nil)
+	}
+	return dst
+}
+
+// fitWithin returns w×h scaled down to fit within maxDim on the longest
+// side, preserving the aspect ratio. Images that already fit are unchanged.
+func fitWithin(w, h, maxDim int) (int, int) {
+	if w <= maxDim && h <= maxDim {
+		return w, h
+	}
+	if w >= h {
+		return maxDim, max(1, h*maxDim/w)
+	}
+	return max(1, w*maxDim/h), maxDim
+}
+
+// exifOrientation returns the EXIF orientation tag (1-8) of a JPEG, or 1 if
+// the data is not a JPEG or has no orientation.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan or end of image: no more metadata segments
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag from IFD0 of a TIFF header.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for k := 0; k < entries; k++ {
+		entry := offset + 2 + k*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// applyOrientation transforms img so that it displays upright given an EXIF
+// orientation value.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // Mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // Transposed
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // Transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotated 90° counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestFitWithin(t *testing.T) {
+	testCases := []struct {
+		w, h, maxDim int
+		wantW, wantH int
+	}{
+		{4000, 3000, 1600, 1600, 1200},
+		{3000, 4000, 800, 600, 800},
+		{640, 480, 800, 640, 480}, // Never upscaled
+		{5000, 10, 200, 200, 1},   // Keeps at least one pixel
+	}
+
+	for _, tc := range testCases {
+		w, h := fitWithin(tc.w, tc.h, tc.maxDim)
+		if w != tc.wantW || h != tc.wantH {
+			t.Errorf("fitWithin(%d, %d, %d) = %dx%d, expected %dx%d",
+				tc.w, tc.h, tc.maxDim, w, h, tc.wantW, tc.wantH)
+		}
+	}
+}
+
+func TestRenderImageVariantsStripsExifAndRotates(t *testing.T) {
+	// A landscape photo taken with the phone held upright: the camera stores
+	// it sideways with orientation 6 (rotate 90° clockwise)
+	src := image.NewRGBA(image.Rect(0, 0, 2000, 1000))
+	for y := 0; y < 1000; y++ {
+		for x := 0; x < 2000; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, nil); err != nil {
+		t.Fatalf("encode fixture: %v", err)
+	}
+	data := withExifOrientation(buf.Bytes(), 6)
+
+	if got := exifOrientation(data); got != 6 {
+		t.Fatalf("Expected orientation 6, got %d", got)
+	}
+
+	variants, width, height, err := renderImageVariants(data)
+	if err != nil {
+		t.Fatalf("renderImageVariants failed: %v", err)
+	}
+	if width != 1000 || height != 2000 {
+		t.Errorf("Expected upright original size 1000x2000, got %dx%d", width, height)
+	}
+
+	expected := map[string][2]int{
+		"large":     {800, 1600},
+		"medium":    {400, 800},
+		"thumbnail": {100, 200},
+	}
+	if len(variants) != len(expected) {
+		t.Fatalf("Expected %d variants, got %d", len(expected), len(variants))
+	}
+	for _, v := range variants {
+		want := expected[v.Name]
+		if v.Width != want[0] || v.Height != want[1] {
+			t.Errorf("Variant %s: expected %dx%d, got %dx%d", v.Name, want[0], want[1], v.Width, v.Height)
+		}
+		if bytes.Contains(v.Data, []byte("Exif\x00\x00")) {
+			t.Errorf("Variant %s still contains EXIF metadata", v.Name)
+		}
+	}
+}
+
+func TestRenderImageVariantsRejectsGarbage(t *testing.T) {
+	if _, _, _, err := renderImageVariants([]byte("definitely not an image")); err == nil {
+		t.Error("Expected error for non-image data")
+	}
+}
+
+// withExifOrientation inserts a minimal big-endian EXIF APP1 segment carrying
+// only the orientation tag right after the JPEG SOI marker.
+func withExifOrientation(jpegData []byte, orientation uint16) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // Header, IFD0 at offset 8
+		0x00, 0x01, // One entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, // Orientation, SHORT, count 1
+		byte(orientation >> 8), byte(orientation), 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // No next IFD
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	size := len(payload) + 2
+
+	out := append([]byte{}, jpegData[:2]...)
+	out = append(out, 0xFF, 0xE1, byte(size>>8), byte(size))
+	out = append(out, payload...)
+	return append(out, jpegData[2:]...)
+}
@@ -0,0 +1,431 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"encore.dev"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/objects"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// ItemImage is an uploaded photo of an item. The original upload is never
+// stored; only the resized, metadata-free variants are.
+type ItemImage struct {
+	ID               uuid.UUID                `json:"id" db:"id"`
+	ItemID           uuid.UUID                `json:"item_id" db:"item_id"`
+	Position         int                      `json:"position" db:"position"`
+	AltText          string                   `json:"alt_text" db:"alt_text"`
+	OriginalFilename string                   `json:"original_filename,omitempty" db:"original_filename"`
+	Width            int                      `json:"width" db:"width"`
+	Height           int                      `json:"height" db:"height"`
+	Variants         map[string]*ImageVariant `json:"variants" db:"variants"` // Keyed by "thumbnail", "medium", "large"
+	CreatedAt        time.Time                `json:"created_at" db:"created_at"`
+}
+
+// ImageVariant is one stored size of an item image
+type ImageVariant struct {
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// AI-CHAT: Item photos bucket, served publicly via CDN.
+// Encore provisions a local bucket when running in development.
+var itemImages = objects.NewBucket("item-images", objects.BucketConfig{
+	Public: true,
+})
+
+const maxImageUploadBytes = 10 << 20 // 10 MB
+
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+//encore:api public raw method=POST path=/v1/items/:id/images
+func UploadItemImage(w http.ResponseWriter, req *http.Request) {
+	// AI-CHAT: Multipart image upload for item listings
+	// Expects an "image" file field and an optional "alt_text" field.
+	// Generates thumbnail/medium/large variants with location data stripped.
+	image, err := uploadItemImage(req.Context(), w, req, encore.CurrentRequest().PathParams.Get("id"))
+	if err != nil {
+		errs.HTTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(image); err != nil {
+		rlog.Error("failed to write upload response", "image_id", image.ID, "err", err)
+	}
+}
+
+//encore:api public method=GET path=/v1/items/:id/images
+func ListItemImages(ctx context.Context, id string) (*ListItemImagesResponse, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	images, err := loadItemImages(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return &ListItemImagesResponse{Images: images}, nil
+}
+
+//encore:api public method=PATCH path=/v1/items/:id/images/:imageID
+func UpdateItemImage(ctx context.Context, id string, imageID string, req *UpdateItemImageRequest) (*ItemImage, error) {
+	// AI-CHAT: Edit alt text so listings stay accessible to screen readers
+	itemID, imgID, err := parseImageIDs(id, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Exec(ctx, `
+		UPDATE item_images SET alt_text = $3
+		WHERE id = $1 AND item_id = $2
+	`, imgID, itemID, req.AltText)
+	if err != nil {
+		return nil, fmt.Errorf("update image: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, errs.B().Code(errs.NotFound).Msgf("image %s not found", imgID).Err()
+	}
+
+	images, err := loadItemImages(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, img := range images {
+		if img.ID == imgID {
+			return img, nil
+		}
+	}
+	return nil, errs.B().Code(errs.NotFound).Msgf("image %s not found", imgID).Err()
+}
+
+//encore:api public method=PUT path=/v1/items/:id/image-order
+func ReorderItemImages(ctx context.Context, id string, req *ReorderItemImagesRequest) (*ListItemImagesResponse, error) {
+	// AI-CHAT: Sets the display order of an item's photos; the first one is
+	// used as the listing's cover image
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockImageItem(ctx, tx, itemID); err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM item_images WHERE item_id = $1", itemID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("count images: %w", err)
+	}
+	seen := make(map[uuid.UUID]bool, len(req.ImageIDs))
+	for _, imgID := range req.ImageIDs {
+		seen[imgID] = true
+	}
+	if len(seen) != count || len(req.ImageIDs) != count {
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("image_ids must list each of the item's %d images exactly once", count).Err()
+	}
+
+	for position, imgID := range req.ImageIDs {
+		result, err := tx.Exec(ctx, `
+			UPDATE item_images SET position = $3 WHERE id = $1 AND item_id = $2
+		`, imgID, itemID, position)
+		if err != nil {
+			return nil, fmt.Errorf("reorder images: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, errs.B().Code(errs.InvalidArgument).Msgf("image %s does not belong to item %s", imgID, itemID).Err()
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit reorder: %w", err)
+	}
+
+	return ListItemImages(ctx, id)
+}
+
+//encore:api public method=DELETE path=/v1/items/:id/images/:imageID
+func DeleteItemImage(ctx context.Context, id string, imageID string) error {
+	itemID, imgID, err := parseImageIDs(id, imageID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockImageItem(ctx, tx, itemID); err != nil {
+		return err
+	}
+
+	var (
+		position int
+		variants []byte
+	)
+	err = tx.QueryRow(ctx, `
+		DELETE FROM item_images WHERE id = $1 AND item_id = $2
+		RETURNING position, variants
+	`, imgID, itemID).Scan(&position, &variants)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return errs.B().Code(errs.NotFound).Msgf("image %s not found", imgID).Err()
+	} else if err != nil {
+		return fmt.Errorf("delete image: %w", err)
+	}
+
+	// Close the gap so positions stay contiguous
+	_, err = tx.Exec(ctx, `
+		UPDATE item_images SET position = position - 1
+		WHERE item_id = $1 AND position > $2
+	`, itemID, position)
+	if err != nil {
+		return fmt.Errorf("compact image positions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit image delete: %w", err)
+	}
+
+	var stored map[string]*ImageVariant
+	if err := json.Unmarshal(variants, &stored); err != nil {
+		return fmt.Errorf("decode image variants: %w", err)
+	}
+	removeImageObjects(ctx, stored)
+	return nil
+}
+
+func uploadItemImage(ctx context.Context, w http.ResponseWriter, req *http.Request, id string) (*ItemImage, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	// Leave headroom for the multipart envelope and the alt_text field
+	req.Body = http.MaxBytesReader(w, req.Body, maxImageUploadBytes+64<<10)
+	if err := req.ParseMultipartForm(maxImageUploadBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("image must be at most 10 MB").Err()
+		}
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("expected a multipart/form-data body").Err()
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	file, header, err := req.FormFile("image")
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg(`missing "image" file field`).Err()
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImageUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if len(data) > maxImageUploadBytes {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("image must be at most 10 MB").Err()
+	}
+	// Trust the bytes rather than the client-supplied Content-Type
+	if contentType := http.DetectContentType(data); !allowedImageTypes[contentType] {
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("unsupported image type %q; use JPEG, PNG or WebP", contentType).Err()
+	}
+
+	var deleted bool
+	err = db.QueryRow(ctx, "SELECT deleted_at IS NOT NULL FROM items WHERE id = $1", itemID).Scan(&deleted)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	if deleted {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("cannot add images to a deleted item").Err()
+	}
+
+	rendered, width, height, err := renderImageVariants(data)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Cause(err).Msg("could not process image").Err()
+	}
+
+	image := &ItemImage{
+		ID:               uuid.New(),
+		ItemID:           itemID,
+		AltText:          req.FormValue("alt_text"),
+		OriginalFilename: header.Filename,
+		Width:            width,
+		Height:           height,
+		Variants:         make(map[string]*ImageVariant, len(rendered)),
+	}
+	for _, v := range rendered {
+		key := fmt.Sprintf("items/%s/%s/%s.jpg", itemID, image.ID, v.Name)
+		if err := storeImageObject(ctx, key, v.Data); err != nil {
+			removeImageObjects(ctx, image.Variants)
+			return nil, err
+		}
+		image.Variants[v.Name] = &ImageVariant{
+			Key:    key,
+			URL:    itemImages.PublicURL(key).String(),
+			Width:  v.Width,
+			Height: v.Height,
+		}
+	}
+
+	variants, err := json.Marshal(image.Variants)
+	if err != nil {
+		return nil, fmt.Errorf("encode image variants: %w", err)
+	}
+	if err := insertItemImage(ctx, image, variants); err != nil {
+		removeImageObjects(ctx, image.Variants)
+		return nil, err
+	}
+	return image, nil
+}
+
+// insertItemImage appends an image after the item's existing ones. The item
+// row is locked first so concurrent uploads can't take the same position.
+func insertItemImage(ctx context.Context, image *ItemImage, variants []byte) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := lockImageItem(ctx, tx, image.ItemID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return errs.B().Code(errs.FailedPrecondition).Msg("cannot add images to a deleted item").Err()
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO item_images (id, item_id, position, alt_text, original_filename, width, height, variants)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM item_images WHERE item_id = $2),
+			$3, $4, $5, $6, $7)
+		RETURNING position, created_at
+	`, image.ID, image.ItemID, image.AltText, image.OriginalFilename, image.Width, image.Height,
+		variants).Scan(&image.Position, &image.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert image: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit image: %w", err)
+	}
+	return nil
+}
+
+// lockImageItem locks an item's row for the rest of tx, serializing changes
+// to its image positions, and reports whether the item is deleted.
+func lockImageItem(ctx context.Context, tx *sqldb.Tx, itemID uuid.UUID) (bool, error) {
+	var deleted bool
+	err := tx.QueryRow(ctx, "SELECT deleted_at IS NOT NULL FROM items WHERE id = $1 FOR UPDATE", itemID).Scan(&deleted)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return false, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return false, fmt.Errorf("lock item: %w", err)
+	}
+	return deleted, nil
+}
+
+func storeImageObject(ctx context.Context, key string, data []byte) error {
+	w := itemImages.Upload(ctx, key, objects.WithUploadAttrs(objects.UploadAttrs{
+		ContentType: "image/jpeg",
+	}))
+	if _, err := w.Write(data); err != nil {
+		w.Abort(err)
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// removeImageObjects deletes stored variants on a best-effort basis; an
+// orphaned object is harmless, so failures are only logged.
+func removeImageObjects(ctx context.Context, variants map[string]*ImageVariant) {
+	for _, v := range variants {
+		if err := itemImages.Remove(ctx, v.Key); err != nil {
+			rlog.Warn("failed to remove image object", "key", v.Key, "err", err)
+		}
+	}
+}
+
+// loadItemImages returns an item's images in display order.
+func loadItemImages(ctx context.Context, itemID uuid.UUID) ([]*ItemImage, error) {
+	rows, err := db.Query(ctx, `
+		SELECT id, item_id, position, alt_text, COALESCE(original_filename, ''), width, height,
+			variants, created_at
+		FROM item_images
+		WHERE item_id = $1
+		ORDER BY position
+	`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("query images: %w", err)
+	}
+	defer rows.Close()
+
+	images := []*ItemImage{}
+	for rows.Next() {
+		var (
+			img      ItemImage
+			variants []byte
+		)
+		err := rows.Scan(&img.ID, &img.ItemID, &img.Position, &img.AltText, &img.OriginalFilename,
+			&img.Width, &img.Height, &variants, &img.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan image: %w", err)
+		}
+		if err := json.Unmarshal(variants, &img.Variants); err != nil {
+			return nil, fmt.Errorf("decode variants for image %s: %w", img.ID, err)
+		}
+		images = append(images, &img)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate images: %w", err)
+	}
+	return images, nil
+}
+
+func parseImageIDs(id, imageID string) (uuid.UUID, uuid.UUID, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	imgID, err := uuid.Parse(imageID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errs.B().Code(errs.InvalidArgument).Msg("invalid image id").Err()
+	}
+	return itemID, imgID, nil
+}
+
+type ListItemImagesResponse struct {
+	Images []*ItemImage `json:"images"`
+}
+
+type UpdateItemImageRequest struct {
+	AltText string `json:"alt_text"`
+}
+
+type ReorderItemImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
@@ -0,0 +1,417 @@
+package catalog
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
+)
+
+// maxImportRows bounds a single import so it fits comfortably in one
+// transaction; split larger intakes into several files.
+const maxImportRows = 5000
+
+// csvImportColumns are the recognised CSV header names. Multiple images are
+// separated by "|" within the images column.
+var csvImportColumns = map[string]bool{
+	"title": true, "description": true, "category": true, "category_id": true,
+	"condition": true, "location": true, "images": true, "width": true,
+	"height": true, "depth": true, "units": true, "weight": true,
+	"buy_now_price": true, "quantity": true, "status": true,
+}
+
+//encore:api public method=POST path=/v1/imports/items
+func ImportItems(ctx context.Context, req *ImportItemsRequest) (*ImportItemsResponse, error) {
+	// AI-CHAT: Bulk warehouse intake, e.g. when a tech company closes and
+	// donates hundreds of chairs and desks at once
+	// Accepts CSV or the seeds/example_items.json shape. Every row is
+	// validated first; nothing is written unless all rows pass, and dry_run
+	// stops after validation.
+
+	var (
+		rows      []*ImportItemRow
+		lines     []int
+		parseErrs []*ImportRowError
+		err       error
+	)
+	switch req.Format {
+	case "", "json":
+		rows = req.Items
+		lines = make([]int, len(rows))
+		for i := range rows {
+			lines[i] = i + 1
+		}
+	case "csv":
+		if rows, lines, parseErrs, err = parseImportCSV(req.CSV); err != nil {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg(err.Error()).Err()
+		}
+	default:
+		return nil, errs.B().Code(errs.InvalidArgument).Msg(`format must be "json" or "csv"`).Err()
+	}
+	if len(rows) == 0 {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("no rows to import").Err()
+	}
+	if len(rows) > maxImportRows {
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("at most %d rows can be imported at once", maxImportRows).Err()
+	}
+
+	categories, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]uuid.UUID, len(categories))
+	byID := make(map[uuid.UUID]bool, len(categories))
+	for _, c := range categories {
+		bySlug[c.Slug] = c.ID
+		byID[c.ID] = true
+	}
+
+	resp := &ImportItemsResponse{
+		Total:  len(rows),
+		DryRun: req.DryRun,
+		Errors: append([]*ImportRowError{}, parseErrs...),
+		Items:  []*Item{},
+	}
+	items := make([]*Item, 0, len(rows))
+	itemLines := make([]int, 0, len(rows))
+	decisions := make(map[uuid.UUID]*screening.Decision)
+	for i, row := range rows {
+		if row == nil {
+			resp.Errors = append(resp.Errors, &ImportRowError{Row: lines[i], Message: "row is empty"})
+			continue
+		}
+		item, rowErrs := importRowToItem(row, lines[i], bySlug, byID, req.CreatedBy)
+		if len(rowErrs) > 0 {
+			resp.Errors = append(resp.Errors, rowErrs...)
+			continue
+		}
+		decision := screenListing(categories, item)
+		switch decision.Action {
+		case screening.ActionBlock:
+			resp.Errors = append(resp.Errors, &ImportRowError{
+				Row:     lines[i],
+				Field:   decision.Deciding().Field,
+				Message: "not allowed under the prohibited items policy: " + decision.Reason(),
+			})
+			continue
+		case screening.ActionReview:
+			item.FlaggedForReview = true
+			if item.Status == string(StatusListed) {
+				item.Status = string(StatusIntake)
+			}
+			decisions[item.ID] = decision
+			resp.Flagged++
+		}
+		items = append(items, item)
+		itemLines = append(itemLines, lines[i])
+	}
+	// A row with a cell parse error may otherwise be valid; it still blocks
+	// the import
+	resp.Valid = len(items) - countRowsWithErrors(parseErrs, itemLines)
+	if len(resp.Errors) > 0 || req.DryRun {
+		return resp, nil
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, item := range items {
+		if err := insertItem(ctx, tx, item); err != nil {
+			switch sqldb.ErrCode(err) {
+			case sqlerr.UniqueViolation:
+				return nil, errs.B().Code(errs.Aborted).Msg("a concurrent write claimed one of the slugs, please retry the import").Err()
+			case sqlerr.ForeignKeyViolation:
+				return nil, errs.B().Code(errs.InvalidArgument).Msgf("row %d: category or created_by does not exist", itemLines[i]).Err()
+			}
+			return nil, fmt.Errorf("insert row %d: %w", itemLines[i], err)
+		}
+		if decision, ok := decisions[item.ID]; ok {
+			if err := recordScreening(ctx, tx, item, decision); err != nil {
+				return nil, fmt.Errorf("record screening decision for row %d: %w", itemLines[i], err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit import: %w", err)
+	}
+
+	for _, item := range items {
+		if err := reindexItem(ctx, item); err != nil {
+			rlog.Error("failed to index imported item", "item_id", item.ID, "err", err)
+		}
+	}
+
+	// TODO: Log import in audit log
+
+	resp.Imported = len(items)
+	resp.Items = items
+	return resp, nil
+}
+
+// importRowToItem converts and validates a single import row, returning every
+// problem found rather than stopping at the first.
+func importRowToItem(row *ImportItemRow, line int, bySlug map[string]uuid.UUID, byID map[uuid.UUID]bool, createdBy uuid.UUID) (*Item, []*ImportRowError) {
+	var rowErrs []*ImportRowError
+	fail := func(field, msg string) {
+		rowErrs = append(rowErrs, &ImportRowError{Row: line, Field: field, Message: msg})
+	}
+
+	item := &Item{
+		ID:          uuid.New(),
+		Title:       strings.TrimSpace(row.Title),
+		Description: row.Description,
+		Condition:   row.Condition,
+		Images:      row.Images,
+		Location:    row.Location,
+		Dimensions:  row.Dimensions,
+		Weight:      row.Weight,
+		BuyNowPrice: row.BuyNowPrice,
+		Quantity:    1,
+		Status:      row.Status,
+		CreatedBy:   createdBy,
+	}
+	if row.Quantity != nil {
+		item.Quantity = *row.Quantity
+	}
+	if item.Images == nil {
+		item.Images = []string{}
+	}
+	if item.Status == "" {
+		item.Status = string(StatusIntake)
+	}
+	if row.CreatedBy != nil {
+		item.CreatedBy = *row.CreatedBy
+	}
+
+	if item.Title == "" {
+		fail("title", "title is required")
+	}
+	if !isValidCondition(item.Condition) {
+		fail("condition", fmt.Sprintf("condition %q must be one of new, like_new, good, fair, parts", item.Condition))
+	}
+	if item.Status != string(StatusIntake) && item.Status != string(StatusListed) {
+		fail("status", "imported items must start as intake or listed")
+	}
+	if item.Weight != nil && *item.Weight < 0 {
+		fail("weight", "weight must not be negative")
+	}
+	if item.BuyNowPrice != nil && *item.BuyNowPrice < 0 {
+		fail("buy_now_price", "buy_now_price must not be negative")
+	}
+	if item.Quantity < 1 {
+		fail("quantity", "quantity must be at least 1")
+	}
+	if d := item.Dimensions; d != nil {
+		if d.Units != "in" && d.Units != "cm" {
+			fail("units", fmt.Sprintf(`dimension units %q must be "in" or "cm"`, d.Units))
+		}
+		if d.Width < 0 || d.Height < 0 || d.Depth < 0 {
+			fail("dimensions", "dimensions must not be negative")
+		}
+	}
+
+	switch {
+	case row.Category != "":
+		id, ok := bySlug[row.Category]
+		if !ok {
+			fail("category", fmt.Sprintf("unknown category slug %q", row.Category))
+		}
+		if row.CategoryID != nil && *row.CategoryID != id {
+			fail("category_id", "category and category_id refer to different categories")
+		}
+		item.CategoryID = id
+	case row.CategoryID != nil:
+		if !byID[*row.CategoryID] {
+			fail("category_id", fmt.Sprintf("unknown category %s", *row.CategoryID))
+		}
+		item.CategoryID = *row.CategoryID
+	}
+
+	return item, rowErrs
+}
+
+// countRowsWithErrors counts the distinct lines in lines that have at least
+// one error in rowErrs.
+func countRowsWithErrors(rowErrs []*ImportRowError, lines []int) int {
+	failed := make(map[int]bool, len(rowErrs))
+	for _, e := range rowErrs {
+		failed[e.Row] = true
+	}
+	n := 0
+	for _, line := range lines {
+		if failed[line] {
+			n++
+		}
+	}
+	return n
+}
+
+// parseImportCSV reads a CSV document with a header row into import rows and
+// the line each came from. Cell-level problems such as a non-numeric weight
+// are returned as row errors so they're reported alongside validation errors.
+func parseImportCSV(data string) (rows []*ImportItemRow, lines []int, rowErrs []*ImportRowError, err error) {
+	r := csv.NewReader(strings.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil, errors.New("csv is empty")
+	} else if err != nil {
+		return nil, nil, nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !csvImportColumns[name] {
+			return nil, nil, nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, nil, nil, errors.New(`csv is missing the "title" column`)
+	}
+
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		row, cellErrs := csvRecordToRow(record, columns, line)
+		rows = append(rows, row)
+		lines = append(lines, line)
+		rowErrs = append(rowErrs, cellErrs...)
+	}
+	return rows, lines, rowErrs, nil
+}
+
+func csvRecordToRow(record []string, columns map[string]int, line int) (*ImportItemRow, []*ImportRowError) {
+	var (
+		row      = &ImportItemRow{}
+		cellErrs []*ImportRowError
+	)
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(name string) *float64 {
+		v := get(name)
+		if v == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			cellErrs = append(cellErrs, &ImportRowError{Row: line, Field: name, Message: fmt.Sprintf("%q is not a number", v)})
+			return nil
+		}
+		return &f
+	}
+
+	row.Title = get("title")
+	row.Description = get("description")
+	row.Category = get("category")
+	row.Condition = get("condition")
+	row.Location = get("location")
+	row.Status = get("status")
+	row.Weight = number("weight")
+	row.BuyNowPrice = number("buy_now_price")
+	if v := get("quantity"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			row.Quantity = &n
+		} else {
+			cellErrs = append(cellErrs, &ImportRowError{Row: line, Field: "quantity", Message: fmt.Sprintf("%q is not a whole number", v)})
+		}
+	}
+	if v := get("category_id"); v != "" {
+		if id, err := uuid.Parse(v); err == nil {
+			row.CategoryID = &id
+		} else {
+			cellErrs = append(cellErrs, &ImportRowError{Row: line, Field: "category_id", Message: fmt.Sprintf("%q is not a valid id", v)})
+		}
+	}
+	if v := get("images"); v != "" {
+		for _, img := range strings.Split(v, "|") {
+			if img = strings.TrimSpace(img); img != "" {
+				row.Images = append(row.Images, img)
+			}
+		}
+	}
+
+	width, height, depth := number("width"), number("height"), number("depth")
+	if units := get("units"); width != nil || height != nil || depth != nil || units != "" {
+		row.Dimensions = &Dimensions{Units: units}
+		if width != nil {
+			row.Dimensions.Width = *width
+		}
+		if height != nil {
+			row.Dimensions.Height = *height
+		}
+		if depth != nil {
+			row.Dimensions.Depth = *depth
+		}
+	}
+	return row, cellErrs
+}
+
+type ImportItemsRequest struct {
+	Format    string           `json:"format"` // "json" (default) or "csv"
+	CSV       string           `json:"csv,omitempty"`
+	Items     []*ImportItemRow `json:"items,omitempty"`
+	DryRun    bool             `json:"dry_run"`
+	CreatedBy uuid.UUID        `json:"created_by"`
+}
+
+// ImportItemRow matches the seeds/example_items.json item shape. Category
+// may be given as a slug, an ID or both; id, slug and created_at in the
+// source are ignored since imports always create new items.
+type ImportItemRow struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Category    string      `json:"category,omitempty"`
+	CategoryID  *uuid.UUID  `json:"category_id,omitempty"`
+	Condition   string      `json:"condition"`
+	Images      []string    `json:"images,omitempty"`
+	Location    string      `json:"location"`
+	Dimensions  *Dimensions `json:"dimensions,omitempty"`
+	Weight      *float64    `json:"weight,omitempty"`
+	BuyNowPrice *float64    `json:"buy_now_price,omitempty"`
+	Quantity    *int        `json:"quantity,omitempty"` // Defaults to 1
+	Status      string      `json:"status,omitempty"`
+	CreatedBy   *uuid.UUID  `json:"created_by,omitempty"`
+}
+
+type ImportItemsResponse struct {
+	Total    int               `json:"total"`
+	Valid    int               `json:"valid"`
+	Imported int               `json:"imported"`
+	Flagged  int               `json:"flagged"` // Valid rows held in intake for prohibited items review
+	DryRun   bool              `json:"dry_run"`
+	Errors   []*ImportRowError `json:"errors"`
+	Items    []*Item           `json:"items"`
+}
+
+// ImportRowError describes one problem with one row. Row is the 1-based
+// position in the JSON array, or the line number in the CSV.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
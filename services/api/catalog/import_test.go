@@ -0,0 +1,95 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	data := `title,category,condition,width,height,depth,units,weight,images
+Herman Miller Aeron Chair,office-chairs,good,27,41,27,in,43,front.jpg|side.jpg
+"Desk, 60""",desks,like_new,,,,,heavy,
+`
+	rows, lines, rowErrs, err := parseImportCSV(data)
+	if err != nil {
+		t.Fatalf("parseImportCSV failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if lines[0] != 2 || lines[1] != 3 {
+		t.Errorf("Expected rows on lines 2 and 3, got %v", lines)
+	}
+
+	chair := rows[0]
+	if chair.Dimensions == nil || chair.Dimensions.Width != 27 || chair.Dimensions.Units != "in" {
+		t.Errorf("Expected 27in wide dimensions, got %+v", chair.Dimensions)
+	}
+	if len(chair.Images) != 2 {
+		t.Errorf("Expected 2 images, got %v", chair.Images)
+	}
+
+	if rows[1].Title != `Desk, 60"` {
+		t.Errorf("Expected quoted title to be unescaped, got %q", rows[1].Title)
+	}
+	if rows[1].Dimensions != nil {
+		t.Errorf("Expected no dimensions for blank cells, got %+v", rows[1].Dimensions)
+	}
+	if len(rowErrs) != 1 || rowErrs[0].Row != 3 || rowErrs[0].Field != "weight" {
+		t.Errorf("Expected a weight error on line 3, got %+v", rowErrs)
+	}
+}
+
+func TestParseImportCSVRejectsUnknownColumns(t *testing.T) {
+	if _, _, _, err := parseImportCSV("title,colour\nChair,blue\n"); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+	if _, _, _, err := parseImportCSV("condition\ngood\n"); err == nil {
+		t.Error("Expected error for missing title column")
+	}
+}
+
+func TestImportRowToItem(t *testing.T) {
+	chairs := uuid.New()
+	bySlug := map[string]uuid.UUID{"office-chairs": chairs}
+	byID := map[uuid.UUID]bool{chairs: true}
+
+	item, rowErrs := importRowToItem(&ImportItemRow{
+		Title:      "Aeron Chair",
+		Category:   "office-chairs",
+		Condition:  "good",
+		Dimensions: &Dimensions{Width: 27, Height: 41, Depth: 27, Units: "in"},
+	}, 1, bySlug, byID, uuid.Nil)
+	if len(rowErrs) != 0 {
+		t.Fatalf("Expected no errors, got %+v", rowErrs)
+	}
+	if item.CategoryID != chairs {
+		t.Errorf("Expected category %s, got %s", chairs, item.CategoryID)
+	}
+	if item.Status != string(StatusIntake) {
+		t.Errorf("Expected imported items to default to intake, got %s", item.Status)
+	}
+	if item.Quantity != 1 {
+		t.Errorf("Expected imported items to default to quantity 1, got %d", item.Quantity)
+	}
+
+	_, rowErrs = importRowToItem(&ImportItemRow{
+		Category:   "sofas",
+		Condition:  "mint",
+		Dimensions: &Dimensions{Width: 10, Units: "ft"},
+		Quantity:   ptr(0),
+	}, 7, bySlug, byID, uuid.Nil)
+	fields := map[string]bool{}
+	for _, e := range rowErrs {
+		if e.Row != 7 {
+			t.Errorf("Expected errors on row 7, got row %d", e.Row)
+		}
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"title", "condition", "units", "category", "quantity"} {
+		if !fields[field] {
+			t.Errorf("Expected an error for %s, got %+v", field, rowErrs)
+		}
+	}
+}
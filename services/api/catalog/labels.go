@@ -0,0 +1,235 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"encore.dev"
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Labels are 2" x 1", the stock loaded in the intake thermal printers.
+const (
+	labelWidthMM  = 50.8
+	labelHeightMM = 25.4
+	labelDPI      = 203 // Zebra thermal printers print at 8 dots/mm
+	skuPrefix     = "SR-"
+)
+
+// itemLabel is the content printed on an item's tag.
+type itemLabel struct {
+	SKU   string
+	Title string
+	URL   string // What the QR code resolves to
+}
+
+// ItemOrder is the order an item was sold in, shown when a tag is scanned at
+// pickup.
+type ItemOrder struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Quantity   int        `json:"quantity"`
+	Total      float64    `json:"total"`
+	Status     string     `json:"status"`
+	PickupDate *time.Time `json:"pickup_date,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+//encore:api public raw method=GET path=/v1/items/:id/label
+func GetItemLabel(w http.ResponseWriter, req *http.Request) {
+	// AI-CHAT: Printable tag for an item: SKU, title and a QR code
+	// ?format=pdf (default) for office printers, ?format=zpl for the Zebra
+	// thermal printers at intake.
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = "pdf"
+	}
+	if format != "pdf" && format != "zpl" {
+		errs.HTTPError(w, errs.B().Code(errs.InvalidArgument).Msg(`format must be "pdf" or "zpl"`).Err())
+		return
+	}
+
+	label, err := loadItemLabel(req.Context(), encore.CurrentRequest().PathParams.Get("id"))
+	if err != nil {
+		errs.HTTPError(w, err)
+		return
+	}
+
+	var body []byte
+	switch format {
+	case "zpl":
+		body = []byte(renderLabelZPL(label))
+		w.Header().Set("Content-Type", "application/vnd.zebra-zpl")
+	default:
+		if body, err = renderLabelPDF(label); err != nil {
+			rlog.Error("failed to render label", "sku", label.SKU, "err", err)
+			errs.HTTPError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.%s"`, label.SKU, format))
+	if _, err := w.Write(body); err != nil {
+		rlog.Error("failed to write label", "sku", label.SKU, "err", err)
+	}
+}
+
+//encore:api public method=GET path=/v1/skus/:sku
+func LookupItemBySKU(ctx context.Context, sku string) (*LookupItemBySKUResponse, error) {
+	// AI-CHAT: Scanner lookup at pickup
+	// Accepts the SKU as printed or as typed by hand; look-alike characters
+	// (O for 0, I or L for 1) are corrected.
+	sku = normalizeSKU(sku)
+	if sku == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("sku is required").Err()
+	}
+
+	item, err := scanItem(db.QueryRow(ctx, "SELECT "+itemColumns+" FROM items i WHERE i.sku = $1", sku))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("no item with sku %s", sku).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+
+	resp := &LookupItemBySKUResponse{Item: item}
+	order := &ItemOrder{}
+	err = db.QueryRow(ctx, `
+		SELECT id, user_id, quantity, COALESCE(total, 0), status, pickup_date, created_at
+		FROM orders
+		WHERE item_id = $1 AND status IN ('pending', 'paid')
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, item.ID).Scan(&order.ID, &order.UserID, &order.Quantity, &order.Total, &order.Status,
+		&order.PickupDate, &order.CreatedAt)
+	if err == nil {
+		resp.Order = order
+	} else if !errors.Is(err, sqldb.ErrNoRows) {
+		return nil, fmt.Errorf("load order: %w", err)
+	}
+	return resp, nil
+}
+
+func loadItemLabel(ctx context.Context, id string) (*itemLabel, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	label := &itemLabel{URL: strings.TrimSuffix(encore.Meta().APIBaseURL.String(), "/") + "/v1/items/" + itemID.String()}
+	err = db.QueryRow(ctx, `
+		SELECT sku, title FROM items WHERE id = $1 AND deleted_at IS NULL
+	`, itemID).Scan(&label.SKU, &label.Title)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	return label, nil
+}
+
+// renderLabelPDF lays out a label as a single-page PDF sized to the label
+// stock. The QR code is drawn as vector squares so it stays sharp at any
+// printer resolution.
+func renderLabelPDF(label *itemLabel) ([]byte, error) {
+	qr, err := qrcode.New(label.URL, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("encode qr code: %w", err)
+	}
+	qr.DisableBorder = true
+	bitmap := qr.Bitmap()
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: labelWidthMM, Ht: labelHeightMM},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	const (
+		margin = 2.0
+		qrSize = labelHeightMM - 2*margin
+	)
+	module := qrSize / float64(len(bitmap))
+	pdf.SetFillColor(0, 0, 0)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				pdf.Rect(margin+float64(x)*module, margin+float64(y)*module, module, module, "F")
+			}
+		}
+	}
+
+	textX := margin + qrSize + margin
+	textWidth := labelWidthMM - textX - margin
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetXY(textX, margin)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(textWidth, 6, label.SKU, "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetX(textX)
+	pdf.MultiCell(textWidth, 3, tr(truncateLabelText(label.Title, 90)), "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderLabelZPL renders a label as ZPL II for Zebra thermal printers, which
+// draw the QR code themselves.
+func renderLabelZPL(label *itemLabel) string {
+	dots := func(mm float64) int { return int(mm * labelDPI / 25.4) }
+	var b strings.Builder
+	b.WriteString("^XA\n^CI28\n")
+	fmt.Fprintf(&b, "^PW%d\n^LL%d\n", dots(labelWidthMM), dots(labelHeightMM))
+	// QR model 2, magnification 4, medium error correction
+	fmt.Fprintf(&b, "^FO16,8^BQN,2,4^FH^FDMA,%s^FS\n", zplEscape(label.URL))
+	fmt.Fprintf(&b, "^FO180,24^A0N,36,36^FH^FD%s^FS\n", zplEscape(label.SKU))
+	fmt.Fprintf(&b, "^FO180,72^FB216,4,0,L^A0N,22,22^FH^FD%s^FS\n", zplEscape(truncateLabelText(label.Title, 90)))
+	b.WriteString("^XZ\n")
+	return b.String()
+}
+
+// zplEscape hex-escapes the characters ZPL treats as commands inside a ^FH
+// field.
+func zplEscape(s string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(s)
+}
+
+// truncateLabelText shortens s to at most n runes, ending in an ellipsis when
+// cut.
+func truncateLabelText(s string, n int) string {
+	runes := []rune(strings.TrimSpace(s))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// normalizeSKU canonicalises a scanned or hand-typed SKU. SKUs use Crockford
+// base32, so letters that look like digits are read as those digits, and the
+// prefix may be left off.
+func normalizeSKU(sku string) string {
+	sku = strings.ToUpper(strings.Join(strings.Fields(sku), ""))
+	sku = strings.TrimPrefix(strings.TrimPrefix(sku, "SR"), "-")
+	if sku == "" {
+		return ""
+	}
+	return skuPrefix + strings.NewReplacer("O", "0", "I", "1", "L", "1").Replace(sku)
+}
+
+type LookupItemBySKUResponse struct {
+	Item  *Item      `json:"item"`
+	Order *ItemOrder `json:"order,omitempty"` // Latest pending or paid order for the item
+}
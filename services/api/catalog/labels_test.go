@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSKU(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"SR-0016J", "SR-0016J"},
+		{"sr-0016j", "SR-0016J"},
+		{"0016J", "SR-0016J"},
+		{" SR 0016J ", "SR-0016J"},
+		{"SR-OO1LJ", "SR-0011J"}, // Look-alike letters typed by hand
+		{"SR0016J", "SR-0016J"},
+		{"", ""},
+		{"SR-", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := normalizeSKU(tc.input); got != tc.expected {
+			t.Errorf("normalizeSKU(%q) = %q, expected %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestRenderLabelZPL(t *testing.T) {
+	zpl := renderLabelZPL(&itemLabel{
+		SKU:   "SR-0016J",
+		Title: "Steelcase Leap ^ V2_black",
+		URL:   "https://api.example.org/v1/items/550e8400-e29b-41d4-a716-446655440000",
+	})
+
+	if !strings.HasPrefix(zpl, "^XA") || !strings.HasSuffix(zpl, "^XZ\n") {
+		t.Errorf("Expected a complete ZPL label, got %q", zpl)
+	}
+	if !strings.Contains(zpl, "^FDMA,https://api.example.org/v1/items/550e8400-e29b-41d4-a716-446655440000^FS") {
+		t.Errorf("Expected QR code with item URL, got %q", zpl)
+	}
+	if !strings.Contains(zpl, "^FDSteelcase Leap _5E V2_5Fblack^FS") {
+		t.Errorf("Expected title with escaped control characters, got %q", zpl)
+	}
+}
+
+func TestRenderLabelPDF(t *testing.T) {
+	pdf, err := renderLabelPDF(&itemLabel{
+		SKU:   "SR-0016J",
+		Title: "Herman Miller Aeron Chair – Size B",
+		URL:   "https://api.example.org/v1/items/550e8400-e29b-41d4-a716-446655440000",
+	})
+	if err != nil {
+		t.Fatalf("renderLabelPDF failed: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("Expected PDF output, got %q", pdf[:min(len(pdf), 16)])
+	}
+}
+
+func TestTruncateLabelText(t *testing.T) {
+	if got := truncateLabelText("Desk", 10); got != "Desk" {
+		t.Errorf("Expected short text unchanged, got %q", got)
+	}
+	if got := truncateLabelText("Standing desk with crank", 10); got != "Standing…" {
+		t.Errorf("Expected truncated text, got %q", got)
+	}
+}
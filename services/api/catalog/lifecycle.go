@@ -0,0 +1,256 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
+)
+
+// ItemStatus tracks where an item is in its reuse lifecycle
+type ItemStatus string
+
+const (
+	StatusIntake        ItemStatus = "intake"
+	StatusListed        ItemStatus = "listed"
+	StatusInAuction     ItemStatus = "in_auction"
+	StatusSold          ItemStatus = "sold"
+	StatusDonatedOnward ItemStatus = "donated_onward"
+	StatusRecycled      ItemStatus = "recycled"
+	StatusArchived      ItemStatus = "archived"
+)
+
+// itemTransitions lists the statuses an item may move to from each status.
+// Sold, donated and recycled items can only be archived; archived items can
+// be relisted if they turn up again in the warehouse.
+var itemTransitions = map[ItemStatus][]ItemStatus{
+	StatusIntake:        {StatusListed, StatusDonatedOnward, StatusRecycled, StatusArchived},
+	StatusListed:        {StatusIntake, StatusInAuction, StatusSold, StatusDonatedOnward, StatusRecycled, StatusArchived},
+	StatusInAuction:     {StatusListed, StatusSold},
+	StatusSold:          {StatusArchived},
+	StatusDonatedOnward: {StatusArchived},
+	StatusRecycled:      {StatusArchived},
+	StatusArchived:      {StatusListed},
+}
+
+func isValidStatus(s string) bool {
+	_, ok := itemTransitions[ItemStatus(s)]
+	return ok
+}
+
+// canTransition reports whether an item may move from one status to another.
+func canTransition(from, to ItemStatus) bool {
+	for _, next := range itemTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+//encore:api public method=PATCH path=/v1/items/:id
+func UpdateItem(ctx context.Context, id string, req *UpdateItemRequest) (*Item, error) {
+	// AI-CHAT: Partial item update for admin/volunteer use
+	// Only fields present in the request change; a new title re-slugs the item
+	// and the old slug keeps redirecting. Status changes follow the lifecycle.
+
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanItem(tx.QueryRow(ctx, "SELECT "+itemColumns+" FROM items i WHERE i.id = $1 FOR UPDATE", itemID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	if item.DeletedAt != nil {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("deleted items cannot be edited").Err()
+	}
+
+	oldTitle, oldDescription, oldCategoryID := item.Title, item.Description, item.CategoryID
+	applyItemUpdate(item, req)
+	if req.Status != nil && *req.Status != item.Status {
+		if !isValidStatus(*req.Status) {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid status").Err()
+		}
+		if !canTransition(ItemStatus(item.Status), ItemStatus(*req.Status)) {
+			return nil, errs.B().Code(errs.FailedPrecondition).Msgf("cannot move item from %s to %s", item.Status, *req.Status).Err()
+		}
+		if item.LotID != nil && (*req.Status == string(StatusInAuction) || *req.Status == string(StatusSold)) {
+			return nil, errs.B().Code(errs.FailedPrecondition).Msg("items in a lot are auctioned and sold with the lot").Err()
+		}
+		if item.FlaggedForReview && (*req.Status == string(StatusListed) || *req.Status == string(StatusInAuction)) {
+			return nil, errs.B().Code(errs.FailedPrecondition).Msg("item is flagged for prohibited items review and cannot be listed until approved").Err()
+		}
+		item.Status = *req.Status
+	}
+	if err := validateItem(item); err != nil {
+		return nil, err
+	}
+
+	// Edits that change what the item is are screened again
+	var decision *screening.Decision
+	if item.Title != oldTitle || item.Description != oldDescription || item.CategoryID != oldCategoryID {
+		if decision, err = screenItem(ctx, item); err != nil {
+			return nil, err
+		}
+		if err := applyScreening(ctx, item, decision); err != nil {
+			return nil, err
+		}
+	}
+
+	if item.Title != oldTitle {
+		if base := generateSlug(item.Title); base != item.Slug {
+			if item.Slug, err = uniqueSlug(ctx, tx, base, item.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	images, dimensions, err := encodeItemJSON(item)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.QueryRow(ctx, `
+		UPDATE items SET slug = $2, title = $3, description = $4, category_id = $5,
+			condition = $6, images = $7, location = $8, dimensions = $9, weight = $10,
+			buy_now_price = $11, status = $12, quantity = $13, flagged_for_review = $14,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, item.ID, item.Slug, item.Title, item.Description, nullUUID(item.CategoryID),
+		item.Condition, images, item.Location, dimensions, item.Weight, item.BuyNowPrice,
+		item.Status, item.Quantity, item.FlaggedForReview).Scan(&item.UpdatedAt)
+	if err != nil {
+		switch sqldb.ErrCode(err) {
+		case sqlerr.UniqueViolation:
+			return nil, errs.B().Code(errs.Aborted).Msg("slug was claimed concurrently, please retry").Err()
+		case sqlerr.ForeignKeyViolation:
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("category_id does not exist").Err()
+		}
+		return nil, fmt.Errorf("update item: %w", err)
+	}
+	if item.Status == string(StatusSold) {
+		// Selling a lot's listing sells everything grouped into the lot
+		_, err = tx.Exec(ctx, `
+			UPDATE items SET status = $2, updated_at = NOW()
+			WHERE lot_id = (SELECT id FROM lots WHERE item_id = $1) AND deleted_at IS NULL
+		`, item.ID, string(StatusSold))
+		if err != nil {
+			return nil, fmt.Errorf("mark lot items sold: %w", err)
+		}
+	}
+	if decision != nil {
+		if err := recordScreening(ctx, tx, item, decision); err != nil {
+			return nil, fmt.Errorf("record screening decision: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit item update: %w", err)
+	}
+
+	if err := reindexItem(ctx, item); err != nil {
+		rlog.Error("failed to reindex updated item", "item_id", item.ID, "err", err)
+	}
+	return item, nil
+}
+
+//encore:api public method=DELETE path=/v1/items/:id
+func DeleteItem(ctx context.Context, id string) error {
+	// AI-CHAT: Soft-deletes an item
+	// The row stays so historical auctions and orders keep a valid reference;
+	// it just disappears from browsing and search.
+
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	result, err := db.Exec(ctx, `
+		UPDATE items SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND status <> $2
+	`, itemID, string(StatusInAuction))
+	if err != nil {
+		return fmt.Errorf("delete item: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		var status string
+		err := db.QueryRow(ctx, "SELECT status FROM items WHERE id = $1 AND deleted_at IS NULL", itemID).Scan(&status)
+		if errors.Is(err, sqldb.ErrNoRows) {
+			return errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+		} else if err != nil {
+			return fmt.Errorf("load item: %w", err)
+		}
+		return errs.B().Code(errs.FailedPrecondition).Msg("items in a running auction cannot be deleted").Err()
+	}
+
+	if err := searchIndex.Remove(ctx, itemID); err != nil {
+		rlog.Error("failed to remove deleted item from search index", "item_id", itemID, "err", err)
+	}
+	return nil
+}
+
+// applyItemUpdate copies the fields present in req onto item.
+func applyItemUpdate(item *Item, req *UpdateItemRequest) {
+	if req.Title != nil {
+		item.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		item.Description = *req.Description
+	}
+	if req.CategoryID != nil {
+		item.CategoryID = *req.CategoryID
+	}
+	if req.Condition != nil {
+		item.Condition = *req.Condition
+	}
+	if req.Images != nil {
+		item.Images = *req.Images
+	}
+	if req.Location != nil {
+		item.Location = *req.Location
+	}
+	if req.Dimensions != nil {
+		item.Dimensions = req.Dimensions
+	}
+	if req.Weight != nil {
+		item.Weight = req.Weight
+	}
+	if req.BuyNowPrice != nil {
+		item.BuyNowPrice = req.BuyNowPrice
+	}
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+	}
+}
+
+// UpdateItemRequest holds a partial item update; nil fields are left as-is.
+type UpdateItemRequest struct {
+	Title       *string     `json:"title,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	CategoryID  *uuid.UUID  `json:"category_id,omitempty"`
+	Condition   *string     `json:"condition,omitempty"`
+	Images      *[]string   `json:"images,omitempty"`
+	Location    *string     `json:"location,omitempty"`
+	Dimensions  *Dimensions `json:"dimensions,omitempty"`
+	Weight      *float64    `json:"weight,omitempty"`
+	BuyNowPrice *float64    `json:"buy_now_price,omitempty"`
+	Quantity    *int        `json:"quantity,omitempty"`
+	Status      *string     `json:"status,omitempty"`
+}
@@ -0,0 +1,45 @@
+package catalog
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	testCases := []struct {
+		from     ItemStatus
+		to       ItemStatus
+		expected bool
+	}{
+		{StatusIntake, StatusListed, true},
+		{StatusListed, StatusInAuction, true},
+		{StatusInAuction, StatusSold, true},
+		{StatusInAuction, StatusListed, true}, // Auction ended unsold
+		{StatusSold, StatusArchived, true},
+		{StatusArchived, StatusListed, true},
+		{StatusIntake, StatusInAuction, false}, // Must be listed first
+		{StatusInAuction, StatusArchived, false},
+		{StatusSold, StatusListed, false},
+		{StatusRecycled, StatusSold, false},
+		{ItemStatus("lost"), StatusListed, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"->"+string(tc.to), func(t *testing.T) {
+			if got := canTransition(tc.from, tc.to); got != tc.expected {
+				t.Errorf("canTransition(%s, %s) = %v, expected %v", tc.from, tc.to, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEveryStatusHasTransitions(t *testing.T) {
+	for _, status := range []ItemStatus{
+		StatusIntake, StatusListed, StatusInAuction, StatusSold,
+		StatusDonatedOnward, StatusRecycled, StatusArchived,
+	} {
+		if !isValidStatus(string(status)) {
+			t.Errorf("Expected %s to be a valid status", status)
+		}
+		if len(itemTransitions[status]) == 0 {
+			t.Errorf("Expected %s to have at least one outgoing transition", status)
+		}
+	}
+}
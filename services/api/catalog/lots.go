@@ -0,0 +1,440 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+)
+
+// Lot groups several items, such as a mystery pallet of office supplies, so
+// they can be listed and auctioned as a single unit. The lot is represented
+// in listings and auctions by its own item (ItemID); the grouped items point
+// back at the lot through Item.LotID.
+type Lot struct {
+	ID               uuid.UUID        `json:"id" db:"id"`
+	ItemID           uuid.UUID        `json:"item_id" db:"item_id"`
+	Quantity         int              `json:"quantity" db:"quantity"`
+	EstimatedWeight  *float64         `json:"estimated_weight,omitempty" db:"estimated_weight"`
+	Manifest         []*ManifestEntry `json:"manifest" db:"manifest"`
+	ManifestComplete bool             `json:"manifest_complete" db:"manifest_complete"` // False for mystery pallets
+	Items            []*Item          `json:"items,omitempty"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+}
+
+// ManifestEntry describes part of a lot's contents that may not be
+// catalogued as individual items.
+type ManifestEntry struct {
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	Condition   string `json:"condition,omitempty"`
+}
+
+//encore:api public method=POST path=/v1/lots
+func CreateLot(ctx context.Context, req *CreateLotRequest) (*Lot, error) {
+	// AI-CHAT: Creates a lot together with the item that lists it
+	// Existing catalogued items can be grouped into the lot straight away;
+	// the rest of the contents can be described in the manifest.
+
+	if req.Item == nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("item is required").Err()
+	}
+	listing := &Item{
+		ID:          uuid.New(),
+		Title:       req.Item.Title,
+		Description: req.Item.Description,
+		CategoryID:  req.Item.CategoryID,
+		Condition:   req.Item.Condition,
+		Images:      req.Item.Images,
+		Location:    req.Item.Location,
+		Dimensions:  req.Item.Dimensions,
+		Weight:      req.Item.Weight,
+		BuyNowPrice: req.Item.BuyNowPrice,
+		Quantity:    1, // The whole lot sells as one unit
+		Status:      req.Item.Status,
+		CreatedBy:   req.Item.CreatedBy,
+	}
+	if listing.Images == nil {
+		listing.Images = []string{}
+	}
+	if listing.Status == "" {
+		listing.Status = string(StatusIntake)
+	}
+	if listing.Status != string(StatusIntake) && listing.Status != string(StatusListed) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("new items must start as intake or listed").Err()
+	}
+	if err := validateItem(listing); err != nil {
+		return nil, err
+	}
+	decision, err := screenItem(ctx, listing)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyScreening(ctx, listing, decision); err != nil {
+		return nil, err
+	}
+
+	lot := &Lot{
+		ID:               uuid.New(),
+		ItemID:           listing.ID,
+		Quantity:         req.Quantity,
+		EstimatedWeight:  req.EstimatedWeight,
+		Manifest:         req.Manifest,
+		ManifestComplete: req.ManifestComplete,
+	}
+	if lot.Manifest == nil {
+		lot.Manifest = []*ManifestEntry{}
+	}
+	if lot.Quantity == 0 {
+		lot.Quantity = len(req.ItemIDs) + manifestQuantity(lot.Manifest)
+	}
+	if err := validateLot(lot); err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(lot.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertItem(ctx, tx, listing); err != nil {
+		switch sqldb.ErrCode(err) {
+		case sqlerr.UniqueViolation:
+			return nil, errs.B().Code(errs.Aborted).Msg("slug was claimed concurrently, please retry").Err()
+		case sqlerr.ForeignKeyViolation:
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("category_id or created_by does not exist").Err()
+		}
+		return nil, fmt.Errorf("insert lot item: %w", err)
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO lots (id, item_id, quantity, estimated_weight, manifest, manifest_complete)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, lot.ID, lot.ItemID, lot.Quantity, lot.EstimatedWeight, manifest, lot.ManifestComplete).Scan(&lot.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert lot: %w", err)
+	}
+	if err := recordScreening(ctx, tx, listing, decision); err != nil {
+		return nil, fmt.Errorf("record screening decision: %w", err)
+	}
+	if err := addItemsToLot(ctx, tx, lot, req.ItemIDs); err != nil {
+		return nil, err
+	}
+	if lot.Items, err = loadLotItems(ctx, tx, lot.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit lot: %w", err)
+	}
+
+	if err := reindexItem(ctx, listing); err != nil {
+		rlog.Error("failed to index new lot item", "item_id", listing.ID, "err", err)
+	}
+	return lot, nil
+}
+
+//encore:api public method=GET path=/v1/lots/:id
+func GetLot(ctx context.Context, id string) (*Lot, error) {
+	// AI-CHAT: Lot detail with its manifest and grouped items
+	lotID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid lot id").Err()
+	}
+	lot, err := scanLot(db.QueryRow(ctx, "SELECT "+lotColumns+" FROM lots l WHERE l.id = $1", lotID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("lot %s not found", lotID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load lot: %w", err)
+	}
+	if lot.Items, err = loadLotItems(ctx, db, lot.ID); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+//encore:api public method=PATCH path=/v1/lots/:id
+func UpdateLot(ctx context.Context, id string, req *UpdateLotRequest) (*Lot, error) {
+	// AI-CHAT: Updates a lot's quantity, weight estimate or manifest
+	// Title, price and status are edited on the lot's item like any other
+	// listing.
+	lot, tx, err := lockLot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if req.Quantity != nil {
+		lot.Quantity = *req.Quantity
+	}
+	if req.EstimatedWeight != nil {
+		lot.EstimatedWeight = req.EstimatedWeight
+	}
+	if req.Manifest != nil {
+		lot.Manifest = *req.Manifest
+	}
+	if req.ManifestComplete != nil {
+		lot.ManifestComplete = *req.ManifestComplete
+	}
+	if err := validateLot(lot); err != nil {
+		return nil, err
+	}
+	manifest, err := json.Marshal(lot.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE lots SET quantity = $2, estimated_weight = $3, manifest = $4, manifest_complete = $5
+		WHERE id = $1
+	`, lot.ID, lot.Quantity, lot.EstimatedWeight, manifest, lot.ManifestComplete)
+	if err != nil {
+		return nil, fmt.Errorf("update lot: %w", err)
+	}
+	if lot.Items, err = loadLotItems(ctx, tx, lot.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit lot update: %w", err)
+	}
+	return lot, nil
+}
+
+//encore:api public method=POST path=/v1/lots/:id/items
+func AddLotItems(ctx context.Context, id string, req *AddLotItemsRequest) (*Lot, error) {
+	// AI-CHAT: Groups more catalogued items into a lot
+	lot, tx, err := lockLot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkLotEditable(ctx, tx, lot); err != nil {
+		return nil, err
+	}
+	if err := addItemsToLot(ctx, tx, lot, req.ItemIDs); err != nil {
+		return nil, err
+	}
+	if lot.Items, err = loadLotItems(ctx, tx, lot.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit lot items: %w", err)
+	}
+	return lot, nil
+}
+
+//encore:api public method=DELETE path=/v1/lots/:id/items/:itemID
+func RemoveLotItem(ctx context.Context, id string, itemID string) (*Lot, error) {
+	// AI-CHAT: Takes an item back out of a lot so it can be listed alone
+	childID, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	lot, tx, err := lockLot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkLotEditable(ctx, tx, lot); err != nil {
+		return nil, err
+	}
+	result, err := tx.Exec(ctx, `
+		UPDATE items SET lot_id = NULL, updated_at = NOW()
+		WHERE id = $1 AND lot_id = $2
+	`, childID, lot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("remove lot item: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s is not in lot %s", childID, lot.ID).Err()
+	}
+	if lot.Items, err = loadLotItems(ctx, tx, lot.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit lot items: %w", err)
+	}
+	return lot, nil
+}
+
+// lotColumns lists the lots columns in the order scanLot expects them.
+// Queries must alias the lots table as "l".
+const lotColumns = `l.id, l.item_id, l.quantity, l.estimated_weight, l.manifest,
+	l.manifest_complete, l.created_at`
+
+func scanLot(row rowScanner) (*Lot, error) {
+	lot := &Lot{}
+	var manifest []byte
+	err := row.Scan(&lot.ID, &lot.ItemID, &lot.Quantity, &lot.EstimatedWeight, &manifest,
+		&lot.ManifestComplete, &lot.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(manifest, &lot.Manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest of lot %s: %w", lot.ID, err)
+	}
+	return lot, nil
+}
+
+// loadLotByItem returns the lot listed by the given item, or nil if the item
+// is not a lot listing.
+func loadLotByItem(ctx context.Context, q querier, itemID uuid.UUID) (*Lot, error) {
+	lot, err := scanLot(q.QueryRow(ctx, "SELECT "+lotColumns+" FROM lots l WHERE l.item_id = $1", itemID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("load lot: %w", err)
+	}
+	if lot.Items, err = loadLotItems(ctx, q, lot.ID); err != nil {
+		return nil, err
+	}
+	return lot, nil
+}
+
+// loadLotItems returns the items grouped into a lot.
+func loadLotItems(ctx context.Context, q querier, lotID uuid.UUID) ([]*Item, error) {
+	rows, err := q.Query(ctx, "SELECT "+itemColumns+`
+		FROM items i
+		WHERE i.lot_id = $1 AND i.deleted_at IS NULL
+		ORDER BY i.created_at, i.id
+	`, lotID)
+	if err != nil {
+		return nil, fmt.Errorf("query lot items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*Item{}
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan lot item: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// lockLot loads a lot for update inside a new transaction. The caller owns
+// the transaction.
+func lockLot(ctx context.Context, id string) (*Lot, *sqldb.Tx, error) {
+	lotID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, nil, errs.B().Code(errs.InvalidArgument).Msg("invalid lot id").Err()
+	}
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	lot, err := scanLot(tx.QueryRow(ctx, "SELECT "+lotColumns+" FROM lots l WHERE l.id = $1 FOR UPDATE", lotID))
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sqldb.ErrNoRows) {
+			return nil, nil, errs.B().Code(errs.NotFound).Msgf("lot %s not found", lotID).Err()
+		}
+		return nil, nil, fmt.Errorf("load lot: %w", err)
+	}
+	return lot, tx, nil
+}
+
+// checkLotEditable refuses changes to a lot's contents once its item is up
+// for auction or has been sold, so bidders get what was listed.
+func checkLotEditable(ctx context.Context, q querier, lot *Lot) error {
+	var status string
+	if err := q.QueryRow(ctx, "SELECT status FROM items WHERE id = $1", lot.ItemID).Scan(&status); err != nil {
+		return fmt.Errorf("load lot item: %w", err)
+	}
+	switch ItemStatus(status) {
+	case StatusIntake, StatusListed:
+		return nil
+	}
+	return errs.B().Code(errs.FailedPrecondition).Msgf("lot contents cannot change while its item is %s", status).Err()
+}
+
+// addItemsToLot assigns items to a lot. Items must be in the warehouse
+// (intake or listed), not already in a lot and not themselves a lot listing.
+func addItemsToLot(ctx context.Context, q querier, lot *Lot, itemIDs []uuid.UUID) error {
+	for _, itemID := range itemIDs {
+		if itemID == lot.ItemID {
+			return errs.B().Code(errs.InvalidArgument).Msg("a lot cannot contain its own item").Err()
+		}
+		result, err := q.Exec(ctx, `
+			UPDATE items i SET lot_id = $2, updated_at = NOW()
+			WHERE i.id = $1 AND i.deleted_at IS NULL AND i.lot_id IS NULL
+				AND i.status IN ($3, $4)
+				AND NOT EXISTS (SELECT 1 FROM lots l WHERE l.item_id = i.id)
+		`, itemID, lot.ID, string(StatusIntake), string(StatusListed))
+		if err != nil {
+			return fmt.Errorf("add item to lot: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return errs.B().Code(errs.FailedPrecondition).Msgf("item %s does not exist, is already in a lot, or is not in intake or listed", itemID).Err()
+		}
+	}
+	return nil
+}
+
+// manifestQuantity sums the quantities in a manifest.
+func manifestQuantity(manifest []*ManifestEntry) int {
+	total := 0
+	for _, entry := range manifest {
+		total += entry.Quantity
+	}
+	return total
+}
+
+func validateLot(lot *Lot) error {
+	if lot.Quantity <= 0 {
+		return errs.B().Code(errs.InvalidArgument).Msg("quantity must be positive").Err()
+	}
+	if lot.EstimatedWeight != nil && *lot.EstimatedWeight < 0 {
+		return errs.B().Code(errs.InvalidArgument).Msg("estimated_weight must not be negative").Err()
+	}
+	for i, entry := range lot.Manifest {
+		if entry == nil || entry.Description == "" {
+			return errs.B().Code(errs.InvalidArgument).Msgf("manifest entry %d needs a description", i+1).Err()
+		}
+		if entry.Quantity <= 0 {
+			return errs.B().Code(errs.InvalidArgument).Msgf("manifest entry %d needs a positive quantity", i+1).Err()
+		}
+		if entry.Condition != "" && !isValidCondition(entry.Condition) {
+			return errs.B().Code(errs.InvalidArgument).Msgf("manifest entry %d has an invalid condition", i+1).Err()
+		}
+	}
+	return nil
+}
+
+// Request/Response types
+
+type CreateLotRequest struct {
+	Item             *CreateItemRequest `json:"item"` // The listing shown to bidders
+	ItemIDs          []uuid.UUID        `json:"item_ids,omitempty"`
+	Quantity         int                `json:"quantity,omitempty"` // Defaults to the grouped items plus manifest quantities
+	EstimatedWeight  *float64           `json:"estimated_weight,omitempty"`
+	Manifest         []*ManifestEntry   `json:"manifest,omitempty"`
+	ManifestComplete bool               `json:"manifest_complete"`
+}
+
+// UpdateLotRequest holds a partial lot update; nil fields are left as-is.
+type UpdateLotRequest struct {
+	Quantity         *int              `json:"quantity,omitempty"`
+	EstimatedWeight  *float64          `json:"estimated_weight,omitempty"`
+	Manifest         *[]*ManifestEntry `json:"manifest,omitempty"`
+	ManifestComplete *bool             `json:"manifest_complete,omitempty"`
+}
+
+type AddLotItemsRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids"`
+}
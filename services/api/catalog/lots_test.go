@@ -0,0 +1,44 @@
+package catalog
+
+import "testing"
+
+func TestValidateLot(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lot   *Lot
+		valid bool
+	}{
+		{"mystery pallet", &Lot{Quantity: 40, EstimatedWeight: ptr(180.0)}, true},
+		{"with manifest", &Lot{Quantity: 6, Manifest: []*ManifestEntry{
+			{Description: "Mesh task chair", Quantity: 4, Condition: "good"},
+			{Description: "Chair mat", Quantity: 2},
+		}}, true},
+		{"zero quantity", &Lot{}, false},
+		{"negative weight", &Lot{Quantity: 1, EstimatedWeight: ptr(-1.0)}, false},
+		{"entry without description", &Lot{Quantity: 1, Manifest: []*ManifestEntry{{Quantity: 1}}}, false},
+		{"entry without quantity", &Lot{Quantity: 1, Manifest: []*ManifestEntry{{Description: "Desk"}}}, false},
+		{"entry with bad condition", &Lot{Quantity: 1, Manifest: []*ManifestEntry{{Description: "Desk", Quantity: 1, Condition: "mint"}}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateLot(tc.lot)
+			if (err == nil) != tc.valid {
+				t.Errorf("validateLot() error = %v, expected valid = %v", err, tc.valid)
+			}
+		})
+	}
+}
+
+func TestManifestQuantity(t *testing.T) {
+	manifest := []*ManifestEntry{
+		{Description: "Monitor arm", Quantity: 3},
+		{Description: "Keyboard tray", Quantity: 5},
+	}
+	if got := manifestQuantity(manifest); got != 8 {
+		t.Errorf("manifestQuantity() = %d, expected 8", got)
+	}
+	if got := manifestQuantity(nil); got != 0 {
+		t.Errorf("manifestQuantity(nil) = %d, expected 0", got)
+	}
+}
@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
+)
+
+//encore:api public method=POST path=/v1/items/:id/review
+func ReviewItem(ctx context.Context, id string, req *ReviewItemRequest) (*Item, error) {
+	// AI-CHAT: Staff decision on an item flagged by prohibited-items screening
+	// Approving clears the flag so the item can be listed; rejecting archives
+	// it. Both are recorded in the audit log next to the original flag.
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	item, err := scanItem(tx.QueryRow(ctx, "SELECT "+itemColumns+" FROM items i WHERE i.id = $1 AND i.deleted_at IS NULL FOR UPDATE", itemID))
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	if !item.FlaggedForReview {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("item is not flagged for review").Err()
+	}
+
+	var role string
+	err = tx.QueryRow(ctx, "SELECT role FROM users WHERE id = $1", req.ReviewerID).Scan(&role)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("reviewer_id does not exist").Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load reviewer: %w", err)
+	}
+	if role == "bidder" {
+		return nil, errs.B().Code(errs.PermissionDenied).Msg("only staff and volunteers can review flagged items").Err()
+	}
+
+	action := "item.screening_approved"
+	item.FlaggedForReview = false
+	if !req.Approve {
+		if req.Note == "" {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("a note is required when rejecting an item").Err()
+		}
+		action = "item.screening_rejected"
+		item.Status = string(StatusArchived)
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE items SET flagged_for_review = FALSE, status = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, item.ID, item.Status).Scan(&item.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("update item: %w", err)
+	}
+	meta, err := json.Marshal(reviewMeta{Note: req.Note})
+	if err != nil {
+		return nil, fmt.Errorf("encode review: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+		VALUES ($1, $2, 'item', $3, $4)
+	`, req.ReviewerID, action, item.ID, meta)
+	if err != nil {
+		return nil, fmt.Errorf("record review: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit review: %w", err)
+	}
+
+	if err := reindexItem(ctx, item); err != nil {
+		rlog.Error("failed to reindex reviewed item", "item_id", item.ID, "err", err)
+	}
+	return item, nil
+}
+
+// screenItem checks an item against the prohibited items rules.
+func screenItem(ctx context.Context, item *Item) (*screening.Decision, error) {
+	categories, err := loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return screenListing(categories, item), nil
+}
+
+// screenListing checks an item's title, description and category, including
+// the category's parents, against the prohibited items rules.
+func screenListing(categories []*Category, item *Item) *screening.Decision {
+	return screening.Default().Screen(&screening.Listing{
+		Title:       item.Title,
+		Description: item.Description,
+		Categories:  categoryPath(categories, item.CategoryID),
+	})
+}
+
+// categoryPath returns the slugs and names of a category and its ancestors.
+func categoryPath(categories []*Category, id uuid.UUID) []string {
+	byID := make(map[uuid.UUID]*Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	var path []string
+	c := byID[id]
+	for c != nil {
+		path = append(path, c.Slug, c.Name)
+		if c.ParentID == nil {
+			break
+		}
+		c = byID[*c.ParentID]
+	}
+	return path
+}
+
+// applyScreening acts on a screening decision for a new or edited item.
+// Prohibited items are rejected, and the attempt is recorded; restricted
+// ones are flagged and held back in intake until staff review them.
+func applyScreening(ctx context.Context, item *Item, decision *screening.Decision) error {
+	switch decision.Action {
+	case screening.ActionBlock:
+		if err := recordScreening(ctx, db, item, decision); err != nil {
+			rlog.Error("failed to record screening decision", "item_id", item.ID, "err", err)
+		}
+		return errs.B().Code(errs.InvalidArgument).Msgf("item is not allowed under the prohibited items policy: %s", decision.Reason()).Err()
+	case screening.ActionReview:
+		item.FlaggedForReview = true
+		if item.Status == string(StatusListed) {
+			item.Status = string(StatusIntake)
+		}
+	}
+	return nil
+}
+
+// recordScreening writes a block or review decision to the audit log. Items
+// that pass screening are not recorded.
+func recordScreening(ctx context.Context, q querier, item *Item, decision *screening.Decision) error {
+	action := "item.screening_flagged"
+	switch decision.Action {
+	case screening.ActionAllow:
+		return nil
+	case screening.ActionBlock:
+		action = "item.screening_blocked"
+	}
+	meta, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("encode screening decision: %w", err)
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+		VALUES ($1, $2, 'item', $3, $4)
+	`, nullUUID(item.CreatedBy), action, item.ID, meta)
+	return err
+}
+
+// reviewMeta is the audit_log meta recorded for a review decision.
+type reviewMeta struct {
+	Note string `json:"note,omitempty"`
+}
+
+type ReviewItemRequest struct {
+	ReviewerID uuid.UUID `json:"reviewer_id"`
+	Approve    bool      `json:"approve"`
+	Note       string    `json:"note,omitempty"` // Required when rejecting
+}
@@ -0,0 +1,38 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
+)
+
+func TestCategoryPath(t *testing.T) {
+	electronics := &Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	phones := &Category{ID: uuid.New(), Name: "Phones", Slug: "phones", ParentID: &electronics.ID}
+	all := []*Category{electronics, phones}
+
+	if got, want := categoryPath(all, phones.ID), []string{"phones", "Phones", "electronics", "Electronics"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("categoryPath() = %v, expected %v", got, want)
+	}
+	if got := categoryPath(all, uuid.Nil); got != nil {
+		t.Errorf("Expected no path for an uncategorised item, got %v", got)
+	}
+}
+
+func TestScreenListingUsesParentCategories(t *testing.T) {
+	electronics := &Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	phones := &Category{ID: uuid.New(), Name: "Phones", Slug: "phones", ParentID: &electronics.ID}
+	all := []*Category{electronics, phones}
+
+	item := &Item{Title: "iPhone 12, iCloud locked", CategoryID: phones.ID}
+	if d := screenListing(all, item); d.Action != screening.ActionReview {
+		t.Errorf("Expected a locked phone to be flagged, got %s", d.Action)
+	}
+	item.CategoryID = uuid.Nil
+	if d := screenListing(all, item); d.Action != screening.ActionAllow {
+		t.Errorf("Expected the electronics-only rule not to apply outside electronics, got %s", d.Action)
+	}
+}
@@ -0,0 +1,156 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Indexer maintains the item search index. The Postgres implementation works
+// out of the box; an external engine such as Meilisearch can be swapped in by
+// assigning a different implementation to searchIndex.
+type Indexer interface {
+	// Index adds or replaces the item's document in the index.
+	Index(ctx context.Context, item *Item) error
+	// Remove drops the item from the index. Removing an unknown item is not an error.
+	Remove(ctx context.Context, itemID uuid.UUID) error
+	// Search returns up to limit hits ordered by descending relevance.
+	Search(ctx context.Context, text string, limit int) ([]*SearchHit, error)
+}
+
+// SearchHit is a single ranked search result.
+type SearchHit struct {
+	ItemID    uuid.UUID        `json:"item_id"`
+	Score     float64          `json:"score"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchHighlight holds HTML snippets with matched terms wrapped in <mark>
+// tags. The item text itself is HTML-escaped.
+type SearchHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// maxSearchHits bounds how many ranked hits are fetched before the regular
+// catalog filters and pagination are applied.
+const maxSearchHits = 1000
+
+var searchIndex Indexer = pgIndexer{}
+
+// ts_headline marks matches with these private-use characters rather than
+// <mark> tags, so the text can be escaped before the tags go in.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+// markHighlight turns a ts_headline snippet into safe HTML: the item text is
+// escaped and only the match markers become <mark> tags.
+func markHighlight(snippet string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// reindexItem updates the search index after an item write. Indexing failures
+// are returned so callers can decide whether they are fatal.
+func reindexItem(ctx context.Context, item *Item) error {
+	if err := searchIndex.Index(ctx, item); err != nil {
+		return fmt.Errorf("index item %s: %w", item.ID, err)
+	}
+	return nil
+}
+
+// pgIndexer implements Indexer on top of the item_search table using
+// weighted tsvector ranking and pg_trgm word similarity for typo tolerance.
+type pgIndexer struct{}
+
+func (pgIndexer) Index(ctx context.Context, item *Item) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO item_search (item_id, title, description, document, updated_at)
+		VALUES ($1, $2, $3,
+			setweight(to_tsvector('english', $2), 'A') || setweight(to_tsvector('english', $3), 'B'),
+			NOW())
+		ON CONFLICT (item_id) DO UPDATE SET
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			document = EXCLUDED.document,
+			updated_at = EXCLUDED.updated_at
+	`, item.ID, item.Title, item.Description)
+	return err
+}
+
+func (pgIndexer) Remove(ctx context.Context, itemID uuid.UUID) error {
+	_, err := db.Exec(ctx, "DELETE FROM item_search WHERE item_id = $1", itemID)
+	return err
+}
+
+func (pgIndexer) Search(ctx context.Context, text string, limit int) ([]*SearchHit, error) {
+	rows, err := db.Query(ctx, `
+		WITH q AS (SELECT websearch_to_tsquery('english', $1) AS tsq)
+		SELECT
+			s.item_id,
+			ts_rank_cd(s.document, q.tsq) + GREATEST(word_similarity($1, s.title), word_similarity($1, s.description) * 0.5) AS score,
+			ts_headline('english', s.title, q.tsq, 'HighlightAll=true, ' || $3),
+			ts_headline('english', s.description, q.tsq, 'MaxWords=30, MinWords=10, MaxFragments=2, ' || $3)
+		FROM item_search s, q
+		WHERE s.document @@ q.tsq OR $1 <% s.title OR $1 <% s.description
+		ORDER BY score DESC, s.item_id
+		LIMIT $2
+	`, text, limit, "StartSel="+highlightStart+", StopSel="+highlightStop)
+	if err != nil {
+		return nil, fmt.Errorf("search items: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*SearchHit
+	for rows.Next() {
+		hit := &SearchHit{Highlight: &SearchHighlight{}}
+		if err := rows.Scan(&hit.ItemID, &hit.Score, &hit.Highlight.Title, &hit.Highlight.Description); err != nil {
+			return nil, fmt.Errorf("scan search hit: %w", err)
+		}
+		hit.Highlight.Title = markHighlight(hit.Highlight.Title)
+		hit.Highlight.Description = markHighlight(hit.Highlight.Description)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search hits: %w", err)
+	}
+	return hits, nil
+}
+
+//encore:api private method=POST path=/v1/internal/search/reindex
+func ReindexItems(ctx context.Context) (*ReindexItemsResponse, error) {
+	// Rebuilds the search index from the items table, e.g. after switching
+	// Indexer implementations or recovering from failed incremental updates
+	rows, err := db.Query(ctx, "SELECT "+itemColumns+" FROM items i ORDER BY i.created_at")
+	if err != nil {
+		return nil, fmt.Errorf("query items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*Item
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate items: %w", err)
+	}
+
+	for _, item := range items {
+		if err := reindexItem(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+	return &ReindexItemsResponse{Indexed: len(items)}, nil
+}
+
+type ReindexItemsResponse struct {
+	Indexed int `json:"indexed"`
+}
@@ -0,0 +1,35 @@
+package catalog
+
+import "testing"
+
+func TestMarkHighlight(t *testing.T) {
+	testCases := []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{"no match", "Oak dining table", "Oak dining table"},
+		{"match", "Oak " + highlightStart + "dining" + highlightStop + " table", "Oak <mark>dining</mark> table"},
+		{"two matches", highlightStart + "oak" + highlightStop + " & " + highlightStart + "pine" + highlightStop,
+			"<mark>oak</mark> &amp; <mark>pine</mark>"},
+		{"markup in title", `<script>alert("x")</script> ` + highlightStart + "lamp" + highlightStop,
+			"&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>lamp</mark>"},
+		{"literal mark tags", "<mark>free</mark> " + highlightStart + "chair" + highlightStop,
+			"&lt;mark&gt;free&lt;/mark&gt; <mark>chair</mark>"},
+		{"empty description", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := markHighlight(tc.snippet); got != tc.expected {
+				t.Errorf("markHighlight(%q) = %q, expected %q", tc.snippet, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDefaultSearchIndex(t *testing.T) {
+	if _, ok := searchIndex.(pgIndexer); !ok {
+		t.Errorf("Expected the Postgres indexer by default, got %T", searchIndex)
+	}
+}
@@ -0,0 +1,265 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComparableSale is a settled auction for an item similar to the one being
+// viewed.
+type ComparableSale struct {
+	ItemID     uuid.UUID `json:"item_id"`
+	AuctionID  uuid.UUID `json:"auction_id"`
+	Title      string    `json:"title"`
+	Condition  string    `json:"condition"`
+	FinalPrice float64   `json:"final_price"`
+	EndedAt    time.Time `json:"ended_at"`
+}
+
+// PriceComparables summarises what similar items have sold for at auction.
+type PriceComparables struct {
+	Sales  []*ComparableSale `json:"sales"`
+	Count  int               `json:"count"`
+	Min    *float64          `json:"min,omitempty"`
+	Median *float64          `json:"median,omitempty"`
+	Max    *float64          `json:"max,omitempty"`
+}
+
+// conditionRank orders conditions from best to worst so they can be
+// compared.
+var conditionRank = map[ItemCondition]int{
+	ConditionNew:     0,
+	ConditionLikeNew: 1,
+	ConditionGood:    2,
+	ConditionFair:    3,
+	ConditionParts:   4,
+}
+
+const (
+	defaultSimilarLimit = 8
+	maxSimilarLimit     = 24
+	maxComparableSales  = 10
+	// similarCandidates bounds how many items in the category are scored.
+	similarCandidates = 200
+	// maxConditionGap is how many condition grades apart a similar item may be.
+	maxConditionGap = 1
+	// maxDimensionDistance is the largest average relative difference in
+	// width, height and depth for items to count as the same size.
+	maxDimensionDistance = 0.35
+	// unknownDimensionDistance is assumed when either item is unmeasured.
+	unknownDimensionDistance = 0.25
+)
+
+//encore:api public method=GET path=/v1/items/:id/similar
+func GetSimilarItems(ctx context.Context, id string, req *GetSimilarItemsRequest) (*GetSimilarItemsResponse, error) {
+	// AI-CHAT: Similar items and price comparisons for an item page
+	// Similar means same category, at most one condition grade apart and
+	// roughly the same size. Comparables are the prices paid for similar
+	// items whose auctions have settled.
+	item, err := GetItem(ctx, id, &GetItemRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	} else if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+
+	resp := &GetSimilarItemsResponse{
+		Items:       []*Item{},
+		Comparables: &PriceComparables{Sales: []*ComparableSale{}},
+	}
+	if item.CategoryID == uuid.Nil {
+		return resp, nil
+	}
+
+	if resp.Items, err = loadSimilarItems(ctx, item, limit); err != nil {
+		return nil, err
+	}
+	if resp.Comparables, err = loadPriceComparables(ctx, item); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// loadSimilarItems returns items for sale in the same category, closest
+// first.
+func loadSimilarItems(ctx context.Context, item *Item, limit int) ([]*Item, error) {
+	rows, err := db.Query(ctx, "SELECT "+itemColumns+`
+		FROM items i
+		WHERE i.category_id = $1 AND i.id <> $2 AND i.deleted_at IS NULL AND i.lot_id IS NULL
+			AND i.status IN ($3, $4)
+		ORDER BY i.created_at DESC
+		LIMIT $5
+	`, item.CategoryID, item.ID, string(StatusListed), string(StatusInAuction), similarCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("query similar items: %w", err)
+	}
+	defer rows.Close()
+
+	type scored struct {
+		item  *Item
+		score float64
+	}
+	var matches []scored
+	for rows.Next() {
+		candidate, err := scanItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan similar item: %w", err)
+		}
+		if score, ok := similarity(item, candidate.Condition, candidate.Dimensions); ok {
+			matches = append(matches, scored{candidate, score})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate similar items: %w", err)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score < matches[j].score })
+	items := []*Item{}
+	for i := 0; i < len(matches) && i < limit; i++ {
+		items = append(items, matches[i].item)
+	}
+	return items, nil
+}
+
+// loadPriceComparables returns the most recent settled auctions of similar
+// items with summary statistics of their final prices: the totals of the
+// paid orders that settled them.
+func loadPriceComparables(ctx context.Context, item *Item) (*PriceComparables, error) {
+	rows, err := db.Query(ctx, `
+		SELECT i.id, a.id, i.title, COALESCE(i.condition, ''), i.dimensions,
+			COALESCE(a.closed_at, a.ends_at), o.total
+		FROM auctions a
+		JOIN items i ON i.id = a.item_id
+		JOIN orders o ON o.auction_id = a.id AND o.status = 'paid'
+		WHERE a.status = 'settled' AND i.category_id = $1 AND i.id <> $2
+		ORDER BY COALESCE(a.closed_at, a.ends_at) DESC
+		LIMIT $3
+	`, item.CategoryID, item.ID, similarCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("query comparable sales: %w", err)
+	}
+	defer rows.Close()
+
+	comparables := &PriceComparables{Sales: []*ComparableSale{}}
+	for rows.Next() && len(comparables.Sales) < maxComparableSales {
+		var (
+			sale       ComparableSale
+			dimensions []byte
+		)
+		err := rows.Scan(&sale.ItemID, &sale.AuctionID, &sale.Title, &sale.Condition, &dimensions,
+			&sale.EndedAt, &sale.FinalPrice)
+		if err != nil {
+			return nil, fmt.Errorf("scan comparable sale: %w", err)
+		}
+		var dims *Dimensions
+		if len(dimensions) > 0 {
+			dims = &Dimensions{}
+			if err := json.Unmarshal(dimensions, dims); err != nil {
+				return nil, fmt.Errorf("decode dimensions for item %s: %w", sale.ItemID, err)
+			}
+		}
+		if _, ok := similarity(item, sale.Condition, dims); ok {
+			comparables.Sales = append(comparables.Sales, &sale)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate comparable sales: %w", err)
+	}
+
+	summarizeComparables(comparables)
+	return comparables, nil
+}
+
+// similarity scores how alike a candidate is to item, lower being closer.
+// ok is false when the candidate is too far off in condition or size.
+func similarity(item *Item, condition string, dimensions *Dimensions) (score float64, ok bool) {
+	a, aKnown := conditionRank[ItemCondition(item.Condition)]
+	b, bKnown := conditionRank[ItemCondition(condition)]
+	if !aKnown || !bKnown {
+		return 0, false
+	}
+	gap := a - b
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > maxConditionGap {
+		return 0, false
+	}
+
+	dist := dimensionDistance(item.Dimensions, dimensions)
+	if dist > maxDimensionDistance {
+		return 0, false
+	}
+	return float64(gap) + dist, true
+}
+
+// dimensionDistance is the average relative difference between the measured
+// sides of two items, from 0 (identical) to 1.
+func dimensionDistance(a, b *Dimensions) float64 {
+	if a == nil || b == nil {
+		return unknownDimensionDistance
+	}
+	as, bs := a.inCentimetres(), b.inCentimetres()
+	var total float64
+	var sides int
+	for k := range as {
+		largest := math.Max(as[k], bs[k])
+		if largest <= 0 {
+			continue
+		}
+		total += math.Abs(as[k]-bs[k]) / largest
+		sides++
+	}
+	if sides == 0 {
+		return unknownDimensionDistance
+	}
+	return total / float64(sides)
+}
+
+// inCentimetres returns width, height and depth in centimetres.
+func (d *Dimensions) inCentimetres() [3]float64 {
+	scale := 1.0
+	if d.Units == "in" {
+		scale = 2.54
+	}
+	return [3]float64{d.Width * scale, d.Height * scale, d.Depth * scale}
+}
+
+// summarizeComparables fills in the count and price statistics.
+func summarizeComparables(c *PriceComparables) {
+	c.Count = len(c.Sales)
+	if c.Count == 0 {
+		return
+	}
+	prices := make([]float64, c.Count)
+	for i, sale := range c.Sales {
+		prices[i] = sale.FinalPrice
+	}
+	sort.Float64s(prices)
+
+	median := prices[c.Count/2]
+	if c.Count%2 == 0 {
+		median = math.Round((prices[c.Count/2-1]+prices[c.Count/2])*50) / 100
+	}
+	c.Min, c.Median, c.Max = ptr(prices[0]), ptr(median), ptr(prices[c.Count-1])
+}
+
+type GetSimilarItemsRequest struct {
+	Limit int `query:"limit"`
+}
+
+type GetSimilarItemsResponse struct {
+	Items       []*Item           `json:"items"`
+	Comparables *PriceComparables `json:"comparables"`
+}
@@ -0,0 +1,77 @@
+package catalog
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	aeron := &Item{
+		Condition:  string(ConditionGood),
+		Dimensions: &Dimensions{Width: 27, Height: 41, Depth: 27, Units: "in"},
+	}
+
+	testCases := []struct {
+		name       string
+		condition  ItemCondition
+		dimensions *Dimensions
+		similar    bool
+	}{
+		{"same chair", ConditionGood, &Dimensions{Width: 27, Height: 41, Depth: 27, Units: "in"}, true},
+		{"same chair in cm", ConditionGood, &Dimensions{Width: 68.6, Height: 104.1, Depth: 68.6, Units: "cm"}, true},
+		{"one grade better", ConditionLikeNew, &Dimensions{Width: 26, Height: 42, Depth: 26, Units: "in"}, true},
+		{"unmeasured", ConditionFair, nil, true},
+		{"two grades worse", ConditionParts, &Dimensions{Width: 27, Height: 41, Depth: 27, Units: "in"}, false},
+		{"much smaller", ConditionGood, &Dimensions{Width: 12, Height: 18, Depth: 12, Units: "in"}, false},
+		{"unknown condition", ItemCondition("mint"), nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := similarity(aeron, string(tc.condition), tc.dimensions); ok != tc.similar {
+				t.Errorf("similarity() ok = %v, expected %v", ok, tc.similar)
+			}
+		})
+	}
+
+	exact, _ := similarity(aeron, string(ConditionGood), aeron.Dimensions)
+	worse, _ := similarity(aeron, string(ConditionFair), aeron.Dimensions)
+	if exact >= worse {
+		t.Errorf("Expected an identical item to score closer (%v) than a worse one (%v)", exact, worse)
+	}
+}
+
+func TestDimensionDistance(t *testing.T) {
+	a := &Dimensions{Width: 10, Height: 20, Depth: 0, Units: "in"}
+	b := &Dimensions{Width: 5, Height: 20, Depth: 0, Units: "in"}
+	// Width differs by half, height matches, depth is unmeasured
+	if got := dimensionDistance(a, b); math.Abs(got-0.25) > 1e-9 {
+		t.Errorf("dimensionDistance() = %v, expected 0.25", got)
+	}
+	if got := dimensionDistance(a, nil); got != unknownDimensionDistance {
+		t.Errorf("dimensionDistance(nil) = %v, expected %v", got, unknownDimensionDistance)
+	}
+}
+
+func TestSummarizeComparables(t *testing.T) {
+	c := &PriceComparables{Sales: []*ComparableSale{
+		{FinalPrice: 180}, {FinalPrice: 95}, {FinalPrice: 240}, {FinalPrice: 120},
+	}}
+	summarizeComparables(c)
+
+	if c.Count != 4 {
+		t.Errorf("Expected count 4, got %d", c.Count)
+	}
+	if c.Min == nil || *c.Min != 95 || c.Max == nil || *c.Max != 240 {
+		t.Errorf("Expected min 95 and max 240, got %v and %v", c.Min, c.Max)
+	}
+	if c.Median == nil || *c.Median != 150 {
+		t.Errorf("Expected median 150, got %v", c.Median)
+	}
+
+	empty := &PriceComparables{}
+	summarizeComparables(empty)
+	if empty.Count != 0 || empty.Median != nil {
+		t.Errorf("Expected no statistics without sales, got %+v", empty)
+	}
+}
@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength caps slugs so URLs stay readable; collision suffixes are
+// counted against the same limit.
+const maxSlugLength = 80
+
+// specialLetters covers letters that don't decompose into an ASCII base
+// letter plus combining marks.
+var specialLetters = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe",
+	"ø", "o", "Ø", "o", "đ", "d", "Đ", "d", "ł", "l", "Ł", "l",
+	"þ", "th", "Þ", "th", "&", " and ", "@", " at ",
+)
+
+// generateSlug derives an SEO-friendly slug from an item title: letters are
+// transliterated to ASCII, lower-cased, and runs of anything else collapse
+// into single hyphens. The result is capped at maxSlugLength on a word
+// boundary where possible.
+func generateSlug(title string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	ascii, _, err := transform.String(t, specialLetters.Replace(title))
+	if err != nil {
+		ascii = title
+	}
+
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(ascii) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		case r == '\'' || r == '’':
+			// Drop apostrophes so "Children's" becomes "childrens"
+		default:
+			pendingHyphen = true
+		}
+	}
+
+	slug := truncateSlug(b.String(), maxSlugLength)
+	if slug == "" {
+		return "item"
+	}
+	return slug
+}
+
+// truncateSlug shortens slug to at most n bytes, preferring to cut at a
+// hyphen so words aren't split.
+func truncateSlug(slug string, n int) string {
+	if len(slug) <= n {
+		return slug
+	}
+	slug = slug[:n]
+	if i := strings.LastIndexByte(slug, '-'); i > n/2 {
+		slug = slug[:i]
+	}
+	return strings.Trim(slug, "-")
+}
+
+// withSlugSuffix appends a numeric collision suffix, cutting the base so the
+// result still fits within maxSlugLength.
+func withSlugSuffix(base string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	if limit := maxSlugLength - len(suffix); len(base) > limit {
+		base = strings.TrimRight(base[:limit], "-")
+	}
+	return base + suffix
+}
+
+// slugPrefix is the part of base shared by every suffixed variant with a
+// suffix of up to seven characters.
+func slugPrefix(base string) string {
+	if limit := maxSlugLength - 8; len(base) > limit {
+		return strings.TrimRight(base[:limit], "-")
+	}
+	return base
+}
+
+// uniqueSlug returns base, or base with the lowest free numeric suffix, such
+// that it collides with neither a current nor a historical slug belonging to
+// another item. excludeID lets an item keep its own slug when re-slugged.
+// Pass a transaction as q to see rows inserted earlier in it.
+func uniqueSlug(ctx context.Context, q querier, base string, excludeID uuid.UUID) (string, error) {
+	rows, err := q.Query(ctx, `
+		SELECT slug FROM items
+		WHERE slug LIKE $1 AND id <> $2
+		UNION
+		SELECT slug FROM item_slug_history
+		WHERE slug LIKE $1 AND item_id <> $2
+	`, escapeLike(slugPrefix(base))+"%", excludeID)
+	if err != nil {
+		return "", fmt.Errorf("query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", fmt.Errorf("scan slug: %w", err)
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("iterate slugs: %w", err)
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if candidate := withSlugSuffix(base, n); !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSlug(t *testing.T) {
+	testCases := []struct {
+		title    string
+		expected string
+	}{
+		{"Herman Miller Aeron Chair - Size B", "herman-miller-aeron-chair-size-b"},
+		{"Electric Standing Desk 60\" - Height Adjustable", "electric-standing-desk-60-height-adjustable"},
+		{"  Café Crème Table  ", "cafe-creme-table"},
+		{"Straßen Lamp & Shade", "strassen-lamp-and-shade"},
+		{"Children's Bookshelf", "childrens-bookshelf"},
+		{"!!!", "item"},
+		{"", "item"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			if got := generateSlug(tc.title); got != tc.expected {
+				t.Errorf("generateSlug(%q) = %q, expected %q", tc.title, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSlugLength(t *testing.T) {
+	title := strings.Repeat("ergonomic mesh ", 20)
+
+	slug := generateSlug(title)
+	if len(slug) > maxSlugLength {
+		t.Errorf("Expected slug of at most %d chars, got %d", maxSlugLength, len(slug))
+	}
+	if strings.HasSuffix(slug, "-") || strings.HasSuffix(slug, "-mes") {
+		t.Errorf("Expected slug cut on a word boundary, got %q", slug)
+	}
+
+	suffixed := withSlugSuffix(slug, 12)
+	if len(suffixed) > maxSlugLength {
+		t.Errorf("Expected suffixed slug of at most %d chars, got %d", maxSlugLength, len(suffixed))
+	}
+	if !strings.HasSuffix(suffixed, "-12") {
+		t.Errorf("Expected suffix -12, got %q", suffixed)
+	}
+	if !strings.HasPrefix(suffixed, slugPrefix(slug)) {
+		t.Errorf("Expected %q to share prefix %q", suffixed, slugPrefix(slug))
+	}
+}
@@ -0,0 +1,63 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"encore.dev/rlog"
+	"github.com/google/uuid"
+)
+
+// maxSessionIDLength bounds the client-supplied session id stored per view.
+const maxSessionIDLength = 128
+
+// recordItemView records that a browser session viewed an item. Repeat views
+// from the same session are ignored. Failures are logged rather than
+// returned; a lost view must never break the item page.
+func recordItemView(ctx context.Context, itemID uuid.UUID, sessionID string, userID uuid.UUID) {
+	sessionID = normalizeSessionID(sessionID)
+	if sessionID == "" {
+		return
+	}
+	_, err := db.Exec(ctx, `
+		INSERT INTO item_views (item_id, session_id, user_id)
+		VALUES ($1, $2, (SELECT id FROM users WHERE id = $3))
+		ON CONFLICT (item_id, session_id) DO NOTHING
+	`, itemID, sessionID, userID)
+	if err != nil {
+		rlog.Error("failed to record item view", "item_id", itemID, "err", err)
+	}
+}
+
+// countItemViews returns how many distinct sessions have viewed an item.
+func countItemViews(ctx context.Context, itemID uuid.UUID) (int, error) {
+	var n int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM item_views WHERE item_id = $1", itemID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count item views: %w", err)
+	}
+	return n, nil
+}
+
+// isPageView reports whether the request comes from a browser viewing the
+// item page. Only page views are recorded and get the view count; internal
+// callers send no session and skip both queries.
+func (r *GetItemRequest) isPageView() bool {
+	return normalizeSessionID(r.SessionID) != ""
+}
+
+// normalizeSessionID trims a session id and drops ones too long to be real.
+func normalizeSessionID(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxSessionIDLength {
+		return ""
+	}
+	return s
+}
+
+type GetItemRequest struct {
+	// SessionID identifies the browser session so repeat views are counted
+	// once. Views are only recorded and counted when it is set.
+	SessionID string    `header:"X-Session-ID"`
+	UserID    uuid.UUID `query:"user_id"` // Signed-in viewer, if any
+}
@@ -0,0 +1,43 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSessionID(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"  3f9c2a  ", "3f9c2a"},
+		{strings.Repeat("a", maxSessionIDLength), strings.Repeat("a", maxSessionIDLength)},
+		{strings.Repeat("a", maxSessionIDLength+1), ""},
+	}
+
+	for _, tc := range testCases {
+		if got := normalizeSessionID(tc.input); got != tc.expected {
+			t.Errorf("normalizeSessionID(%q) = %q, expected %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestIsPageView(t *testing.T) {
+	testCases := []struct {
+		sessionID string
+		expected  bool
+	}{
+		{"", false},
+		{"   ", false},
+		{"3f9c2a", true},
+		{strings.Repeat("a", maxSessionIDLength+1), false},
+	}
+
+	for _, tc := range testCases {
+		req := &GetItemRequest{SessionID: tc.sessionID}
+		if got := req.isPageView(); got != tc.expected {
+			t.Errorf("isPageView() with session %q = %v, expected %v", tc.sessionID, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+// Command seed loads the fixtures in seeds/ into a development database and
+// generates demo auctions and bids on top of them. Every row is upserted with a
+// fixed or derived ID, so running it again refreshes the demo data instead of
+// duplicating it.
+//
+// Usage (from services/api, with `encore run` up so migrations are applied):
+//
+//	go run ./cmd/seed -dsn "$(encore db conn-uri seattle_reuse)"
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"seattlereuse.exchange/api/bidding"
+)
+
+// demoNamespace derives stable IDs for generated rows from the seed IDs.
+var demoNamespace = uuid.MustParse("a5c1e4d0-5eed-4d3e-9c1a-5eed5eed5eed")
+
+// demoMinIncrement is the minimum increment set on every demo auction.
+const demoMinIncrement = 5.0
+
+// demoBidders supplement the seeded users so auctions show a realistic
+// bidding war.
+var demoBidders = []struct {
+	Email string
+	Name  string
+}{
+	{"maria@example.com", "Maria Demo"},
+	{"devon@example.com", "Devon Demo"},
+	{"priya@example.com", "Priya Demo"},
+}
+
+type seedUser struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Phone     *string   `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type seedItem struct {
+	ID          uuid.UUID       `json:"id"`
+	Slug        string          `json:"slug"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	CategoryID  uuid.UUID       `json:"category_id"`
+	Condition   string          `json:"condition"`
+	Images      json.RawMessage `json:"images"`
+	Location    string          `json:"location"`
+	Dimensions  json.RawMessage `json:"dimensions"`
+	Weight      *float64        `json:"weight"`
+	BuyNowPrice *float64        `json:"buy_now_price"`
+	CreatedBy   uuid.UUID       `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// defaultCategories mirror the ones created by migration 004 so the seed
+// also works against a database where they were edited or removed.
+var defaultCategories = []struct {
+	ID   string
+	Name string
+	Slug string
+}{
+	{"750e8400-e29b-41d4-a716-446655440001", "Office Chairs", "office-chairs"},
+	{"750e8400-e29b-41d4-a716-446655440002", "Desks", "desks"},
+	{"750e8400-e29b-41d4-a716-446655440003", "Electronics", "electronics"},
+	{"750e8400-e29b-41d4-a716-446655440004", "Storage & Shelving", "storage-shelving"},
+	{"750e8400-e29b-41d4-a716-446655440005", "Mystery Pallets", "mystery-pallets"},
+	{"750e8400-e29b-41d4-a716-446655440006", "Free Pickup Finds", "free-pickup"},
+}
+
+func main() {
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "Postgres connection string (defaults to $DATABASE_URL)")
+	seedsDir := flag.String("seeds", filepath.Join("..", "..", "seeds"), "directory containing the seed JSON files")
+	flag.Parse()
+
+	if *dsn == "" {
+		log.Fatal(`no database given; pass -dsn "$(encore db conn-uri seattle_reuse)" or set DATABASE_URL`)
+	}
+
+	ctx := context.Background()
+	if err := run(ctx, *dsn, *seedsDir); err != nil {
+		log.Fatalf("seed failed: %v", err)
+	}
+}
+
+func run(ctx context.Context, dsn, seedsDir string) error {
+	var (
+		users []*seedUser
+		items []*seedItem
+	)
+	if err := readJSON(filepath.Join(seedsDir, "example_users.json"), &users); err != nil {
+		return err
+	}
+	if err := readJSON(filepath.Join(seedsDir, "example_items.json"), &items); err != nil {
+		return err
+	}
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	bidders, err := seedUsers(ctx, tx, users)
+	if err != nil {
+		return err
+	}
+	if err := seedCategories(ctx, tx); err != nil {
+		return err
+	}
+	if err := seedItems(ctx, tx, items); err != nil {
+		return err
+	}
+	bidCount, err := seedAuctions(ctx, tx, items, bidders)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	log.Printf("🌱 Seeded %d users, %d categories, %d items, %d auctions and %d bids",
+		len(users)+len(demoBidders), len(defaultCategories), len(items), len(items), bidCount)
+	return nil
+}
+
+// seedUsers upserts the fixture users plus the demo bidders and returns the
+// IDs of everyone who may bid.
+func seedUsers(ctx context.Context, tx pgx.Tx, users []*seedUser) ([]uuid.UUID, error) {
+	var bidders []uuid.UUID
+	for _, u := range users {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO users (id, email, name, role, phone, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id) DO UPDATE SET
+				email = EXCLUDED.email, name = EXCLUDED.name,
+				role = EXCLUDED.role, phone = EXCLUDED.phone
+		`, u.ID, u.Email, u.Name, u.Role, u.Phone, u.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("upsert user %s: %w", u.Email, err)
+		}
+		if u.Role == "bidder" {
+			bidders = append(bidders, u.ID)
+		}
+	}
+
+	for _, b := range demoBidders {
+		id := uuid.NewSHA1(demoNamespace, []byte("user:"+b.Email))
+		_, err := tx.Exec(ctx, `
+			INSERT INTO users (id, email, name, role)
+			VALUES ($1, $2, $3, 'bidder')
+			ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email, name = EXCLUDED.name
+		`, id, b.Email, b.Name)
+		if err != nil {
+			return nil, fmt.Errorf("upsert demo bidder %s: %w", b.Email, err)
+		}
+		bidders = append(bidders, id)
+	}
+	return bidders, nil
+}
+
+func seedCategories(ctx context.Context, tx pgx.Tx) error {
+	for _, c := range defaultCategories {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO categories (id, name, slug)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, slug = EXCLUDED.slug
+		`, c.ID, c.Name, c.Slug)
+		if err != nil {
+			return fmt.Errorf("upsert category %s: %w", c.Slug, err)
+		}
+	}
+	return nil
+}
+
+func seedItems(ctx context.Context, tx pgx.Tx, items []*seedItem) error {
+	for _, it := range items {
+		images := it.Images
+		if len(images) == 0 {
+			images = json.RawMessage("[]")
+		}
+		var dimensions *string
+		if len(it.Dimensions) > 0 && string(it.Dimensions) != "null" {
+			s := string(it.Dimensions)
+			dimensions = &s
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO items (id, slug, title, description, category_id, condition, images,
+				location, dimensions, weight, buy_now_price, status, created_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9::jsonb, $10, $11, 'listed', $12, $13)
+			ON CONFLICT (id) DO UPDATE SET
+				slug = EXCLUDED.slug, title = EXCLUDED.title, description = EXCLUDED.description,
+				category_id = EXCLUDED.category_id, condition = EXCLUDED.condition,
+				images = EXCLUDED.images, location = EXCLUDED.location,
+				dimensions = EXCLUDED.dimensions, weight = EXCLUDED.weight,
+				buy_now_price = EXCLUDED.buy_now_price, status = EXCLUDED.status,
+				quantity = EXCLUDED.quantity, deleted_at = NULL
+		`, it.ID, it.Slug, it.Title, it.Description, it.CategoryID, it.Condition, string(images),
+			it.Location, dimensions, it.Weight, it.BuyNowPrice, it.CreatedBy, it.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("upsert item %s: %w", it.Slug, err)
+		}
+
+		// Keep the catalog search index in step with the upserted rows
+		_, err = tx.Exec(ctx, `
+			INSERT INTO item_search (item_id, title, description, document, updated_at)
+			VALUES ($1, $2, $3,
+				setweight(to_tsvector('english', $2), 'A') || setweight(to_tsvector('english', $3), 'B'),
+				NOW())
+			ON CONFLICT (item_id) DO UPDATE SET
+				title = EXCLUDED.title, description = EXCLUDED.description,
+				document = EXCLUDED.document, updated_at = EXCLUDED.updated_at
+		`, it.ID, it.Title, it.Description)
+		if err != nil {
+			return fmt.Errorf("index item %s: %w", it.Slug, err)
+		}
+	}
+	return nil
+}
+
+// seedAuctions opens one auction per item, staggered to end over the coming
+// days, and fills each with an escalating run of bids. Times are relative to
+// now so re-running the seed keeps the demo auctions live.
+func seedAuctions(ctx context.Context, tx pgx.Tx, items []*seedItem, bidders []uuid.UUID) (int, error) {
+	now := time.Now().Truncate(time.Minute)
+	bidCount := 0
+
+	for i, it := range items {
+		auctionID := uuid.NewSHA1(demoNamespace, []byte("auction:"+it.ID.String()))
+		value := 100.0
+		if it.BuyNowPrice != nil {
+			value = *it.BuyNowPrice
+		}
+
+		// Clear the earlier run's bids and result so amounts stay consistent with the run below
+		if err := resetDemoAuction(ctx, tx, auctionID); err != nil {
+			return 0, fmt.Errorf("reset auction for %s: %w", it.Slug, err)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO auctions (id, item_id, starts_at, ends_at, reserve_price, min_increment, status, anti_sniping_window_sec)
+			VALUES ($1, $2, $3, $4, $5, $6, 'open', 120)
+			ON CONFLICT (id) DO UPDATE SET
+				starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at,
+				reserve_price = EXCLUDED.reserve_price, min_increment = EXCLUDED.min_increment,
+				status = EXCLUDED.status, extension_count = 0
+		`, auctionID, it.ID, now.Add(-24*time.Hour), now.Add(time.Duration(i+1)*24*time.Hour), roundDollars(value*0.5),
+			demoMinIncrement)
+		if err != nil {
+			return 0, fmt.Errorf("upsert auction for %s: %w", it.Slug, err)
+		}
+		if _, err := tx.Exec(ctx, "UPDATE items SET status = 'in_auction' WHERE id = $1", it.ID); err != nil {
+			return 0, fmt.Errorf("mark %s in auction: %w", it.Slug, err)
+		}
+
+		// Start around 30% of buy-now and climb by the smallest raise the bids
+		// service accepts, with bidders taking turns
+		amount := math.Max(roundDollars(value*0.3), bidding.NextMinimumBid(nil, demoMinIncrement))
+		bids := 3 + i%4
+		for n := 0; n < bids && len(bidders) > 0; n++ {
+			bidID := uuid.NewSHA1(demoNamespace, []byte(fmt.Sprintf("bid:%s:%d", auctionID, n)))
+			placedAt := now.Add(-time.Duration(bids-n) * 90 * time.Minute)
+			_, err := tx.Exec(ctx, `
+				INSERT INTO bids (id, auction_id, user_id, amount, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+			`, bidID, auctionID, bidders[(i+n)%len(bidders)], amount, placedAt)
+			if err != nil {
+				return 0, fmt.Errorf("insert bid for %s: %w", it.Slug, err)
+			}
+			bidCount++
+			amount = bidding.NextMinimumBid(&amount, demoMinIncrement)
+		}
+	}
+	return bidCount, nil
+}
+
+// resetDemoAuction clears what an earlier run's auction accumulated once it
+// closed: its result, the winner's order, second-chance offers and the bids
+// they point at. The auction itself is then reopened by the upsert.
+func resetDemoAuction(ctx context.Context, tx pgx.Tx, auctionID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE auctions SET outcome = NULL, closed_at = NULL, winning_bid_id = NULL
+		WHERE id = $1
+	`, auctionID)
+	if err != nil {
+		return fmt.Errorf("clear result: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM auction_offers WHERE auction_id = $1", auctionID); err != nil {
+		return fmt.Errorf("clear offers: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM orders WHERE auction_id = $1", auctionID); err != nil {
+		return fmt.Errorf("clear orders: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM bids WHERE auction_id = $1", auctionID); err != nil {
+		return fmt.Errorf("clear bids: %w", err)
+	}
+	return nil
+}
+
+func roundDollars(v float64) float64 {
+	return float64(int(v + 0.5))
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+-- Slug history for catalog items
+-- Migration: 002_item_slug_history.up.sql
+
+-- Previous slugs keep resolving to their item so old URLs can redirect
+CREATE TABLE item_slug_history (
+    slug TEXT PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_slug_history_item ON item_slug_history(item_id);
+
+-- Record the old slug whenever an item's slug changes
+CREATE FUNCTION record_item_slug_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.slug <> OLD.slug THEN
+        INSERT INTO item_slug_history (slug, item_id)
+        VALUES (OLD.slug, OLD.id)
+        ON CONFLICT (slug) DO UPDATE SET item_id = EXCLUDED.item_id, created_at = NOW();
+
+        -- A slug that becomes current again is no longer historical
+        DELETE FROM item_slug_history WHERE slug = NEW.slug;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER items_slug_history
+    AFTER UPDATE OF slug ON items
+    FOR EACH ROW EXECUTE FUNCTION record_item_slug_change();
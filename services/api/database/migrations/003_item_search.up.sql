@@ -0,0 +1,27 @@
+-- Full-text search index for catalog items
+-- Migration: 003_item_search.up.sql
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Maintained by the catalog service's Postgres indexer on item writes
+CREATE TABLE item_search (
+    item_id UUID PRIMARY KEY REFERENCES items(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    document TSVECTOR NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_search_document ON item_search USING GIN (document);
+CREATE INDEX idx_item_search_title_trgm ON item_search USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_item_search_description_trgm ON item_search USING GIN (description gin_trgm_ops);
+
+-- Backfill items created before search existed
+INSERT INTO item_search (item_id, title, description, document)
+SELECT
+    id,
+    title,
+    COALESCE(description, ''),
+    setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+FROM items;
@@ -0,0 +1,19 @@
+-- Category hierarchy and stable default categories
+-- Migration: 004_category_hierarchy.up.sql
+
+ALTER TABLE categories
+    ADD COLUMN parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+    ADD COLUMN created_at TIMESTAMPTZ DEFAULT NOW(),
+    ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+-- Default categories with fixed IDs so seeds and clients can reference them
+INSERT INTO categories (id, name, slug) VALUES
+    ('750e8400-e29b-41d4-a716-446655440001', 'Office Chairs', 'office-chairs'),
+    ('750e8400-e29b-41d4-a716-446655440002', 'Desks', 'desks'),
+    ('750e8400-e29b-41d4-a716-446655440003', 'Electronics', 'electronics'),
+    ('750e8400-e29b-41d4-a716-446655440004', 'Storage & Shelving', 'storage-shelving'),
+    ('750e8400-e29b-41d4-a716-446655440005', 'Mystery Pallets', 'mystery-pallets'),
+    ('750e8400-e29b-41d4-a716-446655440006', 'Free Pickup Finds', 'free-pickup')
+ON CONFLICT DO NOTHING;
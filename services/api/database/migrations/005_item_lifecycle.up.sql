@@ -0,0 +1,13 @@
+-- Item status lifecycle and soft deletion
+-- Migration: 005_item_lifecycle.up.sql
+
+ALTER TABLE items
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'intake'
+        CHECK (status IN ('intake', 'listed', 'in_auction', 'sold', 'donated_onward', 'recycled', 'archived')),
+    ADD COLUMN updated_at TIMESTAMPTZ,
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Everything cataloged so far was already visible to bidders
+UPDATE items SET status = 'listed';
+
+CREATE INDEX idx_items_status ON items(status) WHERE deleted_at IS NULL;
@@ -0,0 +1,16 @@
+-- Uploaded item images and their resized variants
+-- Migration: 006_item_images.up.sql
+
+CREATE TABLE item_images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id UUID NOT NULL REFERENCES items(id),
+    position INTEGER NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT '',
+    original_filename TEXT,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    variants JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_images_item ON item_images(item_id, position);
@@ -0,0 +1,18 @@
+-- Lots and mystery pallets
+-- Migration: 007_lots.up.sql
+
+-- A lot is listed and auctioned through its own item row; the items it
+-- contains point back at it through items.lot_id
+CREATE TABLE lots (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id UUID NOT NULL UNIQUE REFERENCES items(id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    estimated_weight DECIMAL CHECK (estimated_weight >= 0),
+    manifest JSONB NOT NULL DEFAULT '[]',
+    manifest_complete BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+ALTER TABLE items ADD COLUMN lot_id UUID REFERENCES lots(id);
+
+CREATE INDEX idx_items_lot ON items(lot_id) WHERE lot_id IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Quantity-based inventory and buy-now orders
+-- Migration: 008_item_quantity.up.sql
+
+ALTER TABLE items ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity >= 0);
+
+ALTER TABLE orders ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+-- Auctions are cancelled when a buy-now purchase takes the last unit
+ALTER TABLE auctions DROP CONSTRAINT auctions_status_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_status_check
+    CHECK (status IN ('draft', 'scheduled', 'open', 'closed', 'settled', 'cancelled'));
+
+CREATE INDEX idx_orders_item ON orders(item_id);
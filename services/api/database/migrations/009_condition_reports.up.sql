@@ -0,0 +1,16 @@
+-- Structured condition reports
+-- Migration: 009_condition_reports.up.sql
+
+CREATE TABLE condition_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id UUID NOT NULL REFERENCES items(id),
+    grader_id UUID NOT NULL REFERENCES users(id),
+    checklist TEXT NOT NULL,
+    checks JSONB NOT NULL DEFAULT '[]',
+    unused BOOLEAN NOT NULL DEFAULT FALSE,
+    notes TEXT,
+    grade TEXT NOT NULL CHECK (grade IN ('new', 'like_new', 'good', 'fair', 'parts')),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_condition_reports_item ON condition_reports(item_id, created_at DESC);
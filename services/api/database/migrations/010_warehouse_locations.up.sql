@@ -0,0 +1,33 @@
+-- Physical warehouse locations and pickup scheduling
+-- Migration: 010_warehouse_locations.up.sql
+
+CREATE TABLE warehouses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    address TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE warehouse_zones (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    warehouse_id UUID NOT NULL REFERENCES warehouses(id),
+    code TEXT NOT NULL,
+    name TEXT,
+    UNIQUE (warehouse_id, code)
+);
+
+CREATE TABLE warehouse_bins (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    zone_id UUID NOT NULL REFERENCES warehouse_zones(id),
+    code TEXT NOT NULL,
+    UNIQUE (zone_id, code)
+);
+
+ALTER TABLE items ADD COLUMN bin_id UUID REFERENCES warehouse_bins(id);
+
+-- The day the buyer is collecting a paid order
+ALTER TABLE orders ADD COLUMN pickup_date DATE;
+
+CREATE INDEX idx_items_bin ON items(bin_id) WHERE bin_id IS NOT NULL;
+CREATE INDEX idx_orders_pickup_date ON orders(pickup_date) WHERE pickup_date IS NOT NULL;
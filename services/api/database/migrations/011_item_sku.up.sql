@@ -0,0 +1,25 @@
+-- Short human-readable item SKUs for printed labels
+-- Migration: 011_item_sku.up.sql
+
+CREATE SEQUENCE item_sku_seq;
+
+-- Formats n in Crockford base32 (no I, L, O or U, so SKUs survive being
+-- read aloud or typed by hand), e.g. 1234 -> 'SR-0016J'
+CREATE FUNCTION item_sku(n BIGINT) RETURNS TEXT AS $$
+DECLARE
+    alphabet CONSTANT TEXT := '0123456789ABCDEFGHJKMNPQRSTVWXYZ';
+    encoded TEXT := '';
+BEGIN
+    LOOP
+        encoded := substr(alphabet, (n % 32)::int + 1, 1) || encoded;
+        n := n / 32;
+        EXIT WHEN n = 0;
+    END LOOP;
+    RETURN 'SR-' || lpad(encoded, 5, '0');
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- The volatile default gives every existing item its own SKU
+ALTER TABLE items ADD COLUMN sku TEXT DEFAULT item_sku(nextval('item_sku_seq'));
+ALTER TABLE items ALTER COLUMN sku SET NOT NULL;
+ALTER TABLE items ADD CONSTRAINT items_sku_key UNIQUE (sku);
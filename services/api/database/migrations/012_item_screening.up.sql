@@ -0,0 +1,9 @@
+-- Prohibited items screening
+-- Migration: 012_item_screening.up.sql
+
+-- Set when an item matches a restricted term in the prohibited items rules.
+-- Flagged items stay in intake until staff approve them; the decision and
+-- the matched rules are recorded in audit_log.
+ALTER TABLE items ADD COLUMN flagged_for_review BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_items_flagged_for_review ON items(created_at) WHERE flagged_for_review;
@@ -0,0 +1,29 @@
+-- Item view tracking and watchlists
+-- Migration: 013_views_watchlist.up.sql
+
+-- One row per item per browser session, so reloads don't inflate views
+CREATE TABLE item_views (
+    item_id UUID NOT NULL REFERENCES items(id),
+    session_id TEXT NOT NULL,
+    user_id UUID REFERENCES users(id),
+    viewed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, session_id)
+);
+
+CREATE TABLE watchlist (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    item_id UUID REFERENCES items(id),
+    auction_id UUID REFERENCES auctions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(item_id, auction_id) = 1)
+);
+
+CREATE UNIQUE INDEX idx_watchlist_user_item ON watchlist(user_id, item_id) WHERE item_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_watchlist_user_auction ON watchlist(user_id, auction_id) WHERE auction_id IS NOT NULL;
+CREATE INDEX idx_watchlist_auction ON watchlist(auction_id) WHERE auction_id IS NOT NULL;
+CREATE INDEX idx_item_views_viewed_at ON item_views(viewed_at);
+
+-- Kept in step with watchlist so listings can show demand without a count
+ALTER TABLE items ADD COLUMN watcher_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE auctions ADD COLUMN watcher_count INTEGER NOT NULL DEFAULT 0;
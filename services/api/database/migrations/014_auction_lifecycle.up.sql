@@ -0,0 +1,11 @@
+-- Database-backed auction lifecycle
+-- Migration: 014_auction_lifecycle.up.sql
+
+ALTER TABLE auctions ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE auctions ADD COLUMN updated_at TIMESTAMPTZ;
+-- Times a late bid has pushed ends_at back under the anti-sniping rule
+ALTER TABLE auctions ADD COLUMN extension_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE auctions ADD CONSTRAINT auctions_ends_after_start CHECK (ends_at > starts_at);
+ALTER TABLE auctions ADD CONSTRAINT auctions_amounts_not_negative
+    CHECK (reserve_price >= 0 AND min_increment >= 0 AND anti_sniping_window_sec >= 0);
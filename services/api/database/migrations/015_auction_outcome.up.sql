@@ -0,0 +1,10 @@
+-- Auction results: winner, reserve and the winner's order
+-- Migration: 015_auction_outcome.up.sql
+
+ALTER TABLE auctions ADD COLUMN closed_at TIMESTAMPTZ;
+ALTER TABLE auctions ADD COLUMN outcome TEXT CHECK (outcome IN ('sold', 'reserve_not_met', 'no_bids'));
+ALTER TABLE auctions ADD COLUMN winning_bid_id UUID REFERENCES bids(id);
+
+-- An auction has at most one order awaiting or holding payment
+CREATE UNIQUE INDEX idx_orders_auction_active ON orders(auction_id)
+    WHERE auction_id IS NOT NULL AND status IN ('pending', 'paid');
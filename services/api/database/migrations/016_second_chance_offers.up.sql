@@ -0,0 +1,39 @@
+-- Payment deadlines for auction orders and second-chance offers
+-- Migration: 016_second_chance_offers.up.sql
+
+-- Orders of winners who miss the payment deadline are voided
+ALTER TABLE orders DROP CONSTRAINT orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('pending', 'paid', 'refunded', 'failed', 'voided'));
+ALTER TABLE orders ADD COLUMN payment_due_at TIMESTAMPTZ;
+
+-- Winners already waiting to pay get the standard 48 hours from their win
+UPDATE orders SET payment_due_at = created_at + INTERVAL '48 hours'
+WHERE status = 'pending' AND auction_id IS NOT NULL;
+
+CREATE INDEX idx_orders_payment_due ON orders(payment_due_at)
+    WHERE status = 'pending' AND auction_id IS NOT NULL;
+
+-- 'unpaid': the item sold but neither the winner nor any runner-up paid
+ALTER TABLE auctions DROP CONSTRAINT auctions_outcome_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_outcome_check
+    CHECK (outcome IN ('sold', 'reserve_not_met', 'no_bids', 'unpaid'));
+
+-- Offers to runner-up bidders, made one at a time in bid order
+CREATE TABLE auction_offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    auction_id UUID NOT NULL REFERENCES auctions(id),
+    bid_id UUID NOT NULL REFERENCES bids(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    offered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    order_id UUID REFERENCES orders(id),
+    UNIQUE (auction_id, user_id)
+);
+
+-- At most one offer is open per auction at a time
+CREATE UNIQUE INDEX idx_auction_offers_pending ON auction_offers(auction_id) WHERE status = 'pending';
+CREATE INDEX idx_auction_offers_expires_at ON auction_offers(expires_at) WHERE status = 'pending';
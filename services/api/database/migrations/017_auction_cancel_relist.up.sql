@@ -0,0 +1,14 @@
+-- Auction cancellation and relisting
+-- Migration: 017_auction_cancel_relist.up.sql
+
+-- An item can be auctioned again once an auction is cancelled or doesn't
+-- sell, but only one auction per item may be running at a time
+ALTER TABLE auctions DROP CONSTRAINT auctions_item_id_key;
+CREATE UNIQUE INDEX idx_auctions_item_running ON auctions(item_id)
+    WHERE status IN ('draft', 'scheduled', 'open');
+
+-- The cancelled or unsold auction a relisted auction was cloned from
+ALTER TABLE auctions ADD COLUMN relisted_from UUID REFERENCES auctions(id);
+
+-- Bids on a cancelled auction are voided rather than deleted, to keep the history
+ALTER TABLE bids ADD COLUMN voided_at TIMESTAMPTZ;
@@ -0,0 +1,15 @@
+-- Unique image positions
+-- Migration: 018_item_image_positions.up.sql
+
+-- Renumber any positions that concurrent uploads left duplicated
+UPDATE item_images i SET position = r.n
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY item_id ORDER BY position, created_at, id) - 1 AS n
+    FROM item_images
+) r
+WHERE r.id = i.id AND i.position <> r.n;
+
+-- Deferred so reordering and closing gaps can shuffle positions within a transaction
+DROP INDEX idx_item_images_item;
+ALTER TABLE item_images ADD CONSTRAINT item_images_item_position_key
+    UNIQUE (item_id, position) DEFERRABLE INITIALLY DEFERRED;
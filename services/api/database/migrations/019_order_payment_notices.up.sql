@@ -0,0 +1,10 @@
+-- Payment notices for auction orders
+-- Migration: 019_order_payment_notices.up.sql
+
+-- When the buyer was emailed the link to pay, so a redelivered event doesn't
+-- create another checkout or send the email again
+ALTER TABLE orders ADD COLUMN payment_notified_at TIMESTAMPTZ;
+
+-- Orders already waiting for payment were notified when they were created
+UPDATE orders SET payment_notified_at = created_at
+WHERE status = 'pending' AND auction_id IS NOT NULL;
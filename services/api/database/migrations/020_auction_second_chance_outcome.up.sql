@@ -0,0 +1,15 @@
+-- Auctions whose winner didn't pay
+-- Migration: 020_auction_second_chance_outcome.up.sql
+
+-- 'second_chance': the winner's order was voided and the item is being
+-- offered to runner-up bidders, so the auction has no winner for now
+ALTER TABLE auctions DROP CONSTRAINT auctions_outcome_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_outcome_check
+    CHECK (outcome IN ('sold', 'reserve_not_met', 'no_bids', 'second_chance', 'unpaid'));
+
+-- Stop crediting bidders whose order was voided with the win
+UPDATE auctions a SET outcome = 'second_chance', winning_bid_id = NULL
+WHERE a.outcome = 'sold' AND NOT EXISTS (
+    SELECT 1 FROM orders o WHERE o.auction_id = a.id AND o.status IN ('pending', 'paid')
+);
+UPDATE auctions SET winning_bid_id = NULL WHERE outcome = 'unpaid';
@@ -1,6 +1,20 @@
 package donations
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"encore.dev/beta/errs"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/screening"
+)
+
+var db = sqldb.Named("seattle_reuse")
 
 //encore:api public method=POST path=/v1/donations/cash
 func CreateCashDonation(ctx context.Context, req *CashDonationRequest) (*DonationResponse, error) {
@@ -11,7 +25,89 @@ func CreateCashDonation(ctx context.Context, req *CashDonationRequest) (*Donatio
 //encore:api public method=POST path=/v1/donations/goods
 func CreateGoodsDonation(ctx context.Context, req *GoodsDonationRequest) (*DonationResponse, error) {
 	// AI-CHAT: Goods donation intake with photo upload and condition assessment
-	return &DonationResponse{ReceiptID: "goods-456"}, nil
+	// Offers are screened against the prohibited items policy before anyone
+	// drives them to the warehouse: prohibited goods are turned away, and
+	// restricted ones are accepted but held for staff review.
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("description is required").Err()
+	}
+	photos := req.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+
+	donationID := uuid.New()
+	decision := screening.Default().Screen(&screening.Listing{Description: description})
+	if decision.Action == screening.ActionBlock {
+		if err := recordScreening(ctx, db, donationID, decision); err != nil {
+			rlog.Error("failed to record screening decision", "donation_id", donationID, "err", err)
+		}
+		return nil, errs.B().Code(errs.InvalidArgument).Msgf("we cannot accept this donation under the prohibited items policy: %s", decision.Reason()).Err()
+	}
+
+	photosJSON, err := json.Marshal(photos)
+	if err != nil {
+		return nil, fmt.Errorf("encode photos: %w", err)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Donors aren't required to have an account; link one if the email
+	// matches. Emails are only unique case-sensitively, so prefer an exact
+	// match, then the oldest account.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO donations_goods (id, user_id, description, photos, status)
+		VALUES ($1, (
+			SELECT id FROM users WHERE lower(email) = lower($2)
+			ORDER BY email = $2 DESC, created_at, id
+			LIMIT 1
+		), $3, $4, 'submitted')
+	`, donationID, req.Email, description, photosJSON)
+	if err != nil {
+		return nil, fmt.Errorf("insert goods donation: %w", err)
+	}
+	if err := recordScreening(ctx, tx, donationID, decision); err != nil {
+		return nil, fmt.Errorf("record screening decision: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit goods donation: %w", err)
+	}
+
+	return &DonationResponse{
+		ReceiptID:   donationID.String(),
+		NeedsReview: decision.Action == screening.ActionReview,
+	}, nil
+}
+
+// execer is satisfied by both *sqldb.Database and *sqldb.Tx.
+type execer interface {
+	Exec(ctx context.Context, query string, args ...interface{}) (sqldb.ExecResult, error)
+}
+
+// recordScreening writes a block or review decision for a goods donation to
+// the audit log. Donations that pass screening are not recorded.
+func recordScreening(ctx context.Context, q execer, donationID uuid.UUID, decision *screening.Decision) error {
+	action := "goods_donation.screening_flagged"
+	switch decision.Action {
+	case screening.ActionAllow:
+		return nil
+	case screening.ActionBlock:
+		action = "goods_donation.screening_blocked"
+	}
+	meta, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("encode screening decision: %w", err)
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO audit_log (action, entity, entity_id, meta)
+		VALUES ($1, 'goods_donation', $2, $3)
+	`, action, donationID, meta)
+	return err
 }
 
 type CashDonationRequest struct {
@@ -26,5 +122,6 @@ type GoodsDonationRequest struct {
 }
 
 type DonationResponse struct {
-	ReceiptID string `json:"receipt_id"`
-}
\ No newline at end of file
+	ReceiptID   string `json:"receipt_id"`
+	NeedsReview bool   `json:"needs_review,omitempty"` // Goods that staff must approve before drop-off
+}
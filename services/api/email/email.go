@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"bytes"
+	"html"
 	"net/http"
 )
 
@@ -193,6 +194,48 @@ func SendBidConfirmation(ctx context.Context, req *BidConfirmationEmailRequest)
 	return SendEmail(ctx, emailReq)
 }
 
+// AI-CHAT: Offer an unpaid auction win to the next-highest bidder at their own bid
+//encore:api public method=POST path=/email/second-chance-offer
+func SendSecondChanceOffer(ctx context.Context, req *SecondChanceOfferEmailRequest) (*EmailResponse, error) {
+	emailReq := &EmailRequest{
+		From:    "Seattle Reuse Exchange <auctions@seattlereuse.exchange>",
+		To:      []string{req.BidderEmail},
+		Subject: fmt.Sprintf("It's yours if you want it: %s - Auction #%s", req.ItemTitle, req.AuctionID),
+		HTML:    generateSecondChanceOfferHTML(req),
+		ReplyTo: "support@seattlereuse.exchange",
+	}
+
+	return SendEmail(ctx, emailReq)
+}
+
+// AI-CHAT: Tell a bidder an auction they bid on was cancelled and their bid voided
+//encore:api public method=POST path=/email/auction-cancelled
+func SendAuctionCancelledNotice(ctx context.Context, req *AuctionCancelledEmailRequest) (*EmailResponse, error) {
+	emailReq := &EmailRequest{
+		From:    "Seattle Reuse Exchange <auctions@seattlereuse.exchange>",
+		To:      []string{req.BidderEmail},
+		Subject: fmt.Sprintf("Auction cancelled: %s - Auction #%s", req.ItemTitle, req.AuctionID),
+		HTML:    generateAuctionCancelledHTML(req),
+		ReplyTo: "support@seattlereuse.exchange",
+	}
+
+	return SendEmail(ctx, emailReq)
+}
+
+// AI-CHAT: Tell a watcher a late bid pushed back the end of an auction they follow
+//encore:api public method=POST path=/email/auction-extended
+func SendAuctionExtendedNotice(ctx context.Context, req *AuctionExtendedEmailRequest) (*EmailResponse, error) {
+	emailReq := &EmailRequest{
+		From:    "Seattle Reuse Exchange <auctions@seattlereuse.exchange>",
+		To:      []string{req.WatcherEmail},
+		Subject: fmt.Sprintf("More time to bid: %s - Auction #%s", req.ItemTitle, req.AuctionID),
+		HTML:    generateAuctionExtendedHTML(req),
+		ReplyTo: "support@seattlereuse.exchange",
+	}
+
+	return SendEmail(ctx, emailReq)
+}
+
 // AI-CHAT: Request structures for specialized email types
 type AuctionWinEmailRequest struct {
 	WinnerEmail     string  `json:"winner_email"`
@@ -224,6 +267,33 @@ type BidConfirmationEmailRequest struct {
 	ItemURL       string  `json:"item_url"`
 }
 
+type SecondChanceOfferEmailRequest struct {
+	BidderEmail string  `json:"bidder_email"`
+	BidderName  string  `json:"bidder_name"`
+	AuctionID   string  `json:"auction_id"`
+	ItemTitle   string  `json:"item_title"`
+	OfferAmount float64 `json:"offer_amount"`
+	ExpiresAt   string  `json:"expires_at"`
+	OfferURL    string  `json:"offer_url"`
+}
+
+type AuctionCancelledEmailRequest struct {
+	BidderEmail string `json:"bidder_email"`
+	BidderName  string `json:"bidder_name"`
+	AuctionID   string `json:"auction_id"`
+	ItemTitle   string `json:"item_title"`
+	Reason      string `json:"reason"`
+}
+
+type AuctionExtendedEmailRequest struct {
+	WatcherEmail string `json:"watcher_email"`
+	WatcherName  string `json:"watcher_name"`
+	AuctionID    string `json:"auction_id"`
+	ItemTitle    string `json:"item_title"`
+	NewEndTime   string `json:"new_end_time"`
+	AuctionURL   string `json:"auction_url"`
+}
+
 // AI-CHAT: Email validation helper
 func validateEmailRequest(req *EmailRequest) error {
 	if req.From == "" {
@@ -306,4 +376,46 @@ func generateBidConfirmationHTML(req *BidConfirmationEmailRequest) string {
 			</div>
 		</div>
 	`, req.ItemTitle, status, req.BidAmount, req.CurrentHigh, req.NextMinBid, req.AuctionEndTime, req.ItemURL)
-}
\ No newline at end of file
+}
+
+func generateSecondChanceOfferHTML(req *SecondChanceOfferEmailRequest) string {
+	return fmt.Sprintf(`
+		<div style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto;">
+			<h1>Good news, %s!</h1>
+			<p>The winner of <strong>%s</strong> didn't complete their purchase, and you were the next-highest bidder.</p>
+			<p><strong>Your Bid:</strong> $%.2f</p>
+			<p>The item is yours at your bid if you accept by <strong>%s</strong>. After that it will be offered to the next bidder.</p>
+			<div style="text-align: center; margin: 30px 0;">
+				<a href="%s" style="background-color: #16a34a; color: white; padding: 15px 30px; text-decoration: none; border-radius: 5px; display: inline-block;">
+					View Offer
+				</a>
+			</div>
+		</div>
+	`, req.BidderName, req.ItemTitle, req.OfferAmount, req.ExpiresAt, req.OfferURL)
+}
+
+func generateAuctionCancelledHTML(req *AuctionCancelledEmailRequest) string {
+	return fmt.Sprintf(`
+		<div style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto;">
+			<h1>Sorry, %s</h1>
+			<p>The auction for <strong>%s</strong> has been cancelled, and your bid on it no longer stands.</p>
+			<p><strong>Reason:</strong> %s</p>
+			<p><em>Thanks for bidding, and we hope to see you at our next auction!</em></p>
+		</div>
+	`, req.BidderName, req.ItemTitle, html.EscapeString(req.Reason))
+}
+
+func generateAuctionExtendedHTML(req *AuctionExtendedEmailRequest) string {
+	return fmt.Sprintf(`
+		<div style="font-family: Arial, sans-serif; max-width: 600px; margin: 0 auto;">
+			<h1>Hi %s, the auction is heating up!</h1>
+			<p>A last-minute bid on <strong>%s</strong> extended the auction.</p>
+			<p><strong>New End Time:</strong> %s</p>
+			<div style="text-align: center; margin: 30px 0;">
+				<a href="%s" style="background-color: #dc2626; color: white; padding: 15px 30px; text-decoration: none; border-radius: 5px; display: inline-block;">
+					View Auction
+				</a>
+			</div>
+		</div>
+	`, req.WatcherName, req.ItemTitle, req.NewEndTime, req.AuctionURL)
+}
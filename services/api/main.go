@@ -15,6 +15,8 @@ import (
 	_ "seattlereuse.exchange/api/webhooks"
 	_ "seattlereuse.exchange/api/reports"
 	_ "seattlereuse.exchange/api/email"
+	_ "seattlereuse.exchange/api/warehouse"
+	_ "seattlereuse.exchange/api/watchlist"
 )
 
 func main() {
@@ -0,0 +1,248 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/pubsub"
+	"encore.dev/rlog"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/auctions"
+	"seattlereuse.exchange/api/bids"
+	"seattlereuse.exchange/api/email"
+	"seattlereuse.exchange/api/orders"
+)
+
+var db = sqldb.Named("seattle_reuse")
+
+// siteURL is where links in emails point.
+const siteURL = "https://seattlereuse.exchange"
+
+var _ = pubsub.NewSubscription(auctions.AuctionClosed, "notify-auction-winner", pubsub.SubscriptionConfig[*auctions.AuctionClosedEvent]{
+	Handler: notifyAuctionWinner,
+})
+
+// notifyAuctionWinner emails the winner of a sold auction a link to pay.
+func notifyAuctionWinner(ctx context.Context, event *auctions.AuctionClosedEvent) error {
+	if event.Outcome != auctions.OutcomeSold || event.OrderID == nil {
+		return nil
+	}
+	return notifyOrderPayment(ctx, *event.OrderID)
+}
+
+var _ = pubsub.NewSubscription(auctions.SecondChanceAccepted, "notify-second-chance-accepted", pubsub.SubscriptionConfig[*auctions.SecondChanceAcceptedEvent]{
+	Handler: notifySecondChanceAccepted,
+})
+
+// notifySecondChanceAccepted sends a runner-up who accepted an offer the same
+// link to pay as an auction winner.
+func notifySecondChanceAccepted(ctx context.Context, event *auctions.SecondChanceAcceptedEvent) error {
+	return notifyOrderPayment(ctx, event.OrderID)
+}
+
+// notifyOrderPayment emails the buyer of a pending auction order a link to
+// pay. The order is claimed before anything is sent, so each one gets a
+// single checkout and email however often the event is delivered; the claim
+// is released if sending fails so the redelivery tries again.
+func notifyOrderPayment(ctx context.Context, orderID uuid.UUID) error {
+	req := &email.AuctionWinEmailRequest{}
+	err := db.QueryRow(ctx, `
+		UPDATE orders o SET payment_notified_at = NOW()
+		FROM users u, items i
+		WHERE o.id = $1 AND o.status = 'pending' AND o.payment_notified_at IS NULL
+			AND u.id = o.user_id AND i.id = o.item_id
+		RETURNING o.auction_id::text, o.total, u.email, COALESCE(u.name, ''), i.title,
+			COALESCE(i.location, '')
+	`, orderID).Scan(&req.AuctionID, &req.WinningBid, &req.WinnerEmail, &req.WinnerName, &req.ItemTitle,
+		&req.PickupLocation)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		// Already notified, no longer awaiting payment, or its buyer or item is gone
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("claim order notice: %w", err)
+	}
+
+	if err := sendPaymentNotice(ctx, orderID, req); err != nil {
+		if _, releaseErr := db.Exec(ctx, "UPDATE orders SET payment_notified_at = NULL WHERE id = $1", orderID); releaseErr != nil {
+			rlog.Error("failed to release order notice", "order_id", orderID, "err", releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// sendPaymentNotice creates a checkout for a claimed order and emails req
+// with the link to it.
+func sendPaymentNotice(ctx context.Context, orderID uuid.UUID, req *email.AuctionWinEmailRequest) error {
+	checkout, err := orders.CreateStripeCheckout(ctx, &orders.CheckoutRequest{OrderID: orderID.String()})
+	if err != nil {
+		return fmt.Errorf("create checkout: %w", err)
+	}
+	req.PaymentLink = checkout.CheckoutURL
+
+	resp, err := email.SendAuctionWinNotification(ctx, req)
+	if err != nil {
+		return fmt.Errorf("send winner email: %w", err)
+	}
+	if !resp.Success {
+		// Not worth retrying; the message itself was rejected
+		rlog.Error("winner email rejected", "order_id", orderID, "message", resp.Message)
+	}
+	return nil
+}
+
+var _ = pubsub.NewSubscription(auctions.SecondChanceOffered, "notify-second-chance-offer", pubsub.SubscriptionConfig[*auctions.SecondChanceOfferEvent]{
+	Handler: notifySecondChanceOffer,
+})
+
+// notifySecondChanceOffer emails a runner-up bidder the offer of an item
+// whose winner didn't pay.
+func notifySecondChanceOffer(ctx context.Context, event *auctions.SecondChanceOfferEvent) error {
+	req := &email.SecondChanceOfferEmailRequest{
+		AuctionID:   event.AuctionID.String(),
+		OfferAmount: event.Amount,
+		ExpiresAt:   event.ExpiresAt.Format(time.RFC1123),
+		OfferURL:    fmt.Sprintf("%s/auctions/%s/offers/%s", siteURL, event.AuctionID, event.OfferID),
+	}
+	err := db.QueryRow(ctx, `
+		SELECT u.email, COALESCE(u.name, ''), i.title
+		FROM users u, items i
+		WHERE u.id = $1 AND i.id = $2
+	`, event.UserID, event.ItemID).Scan(&req.BidderEmail, &req.BidderName, &req.ItemTitle)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		rlog.Warn("offered bidder or item no longer exists", "auction_id", event.AuctionID, "offer_id", event.OfferID)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("load offered bidder: %w", err)
+	}
+
+	resp, err := email.SendSecondChanceOffer(ctx, req)
+	if err != nil {
+		return fmt.Errorf("send offer email: %w", err)
+	}
+	if !resp.Success {
+		rlog.Error("offer email rejected", "offer_id", event.OfferID, "message", resp.Message)
+	}
+	return nil
+}
+
+var _ = pubsub.NewSubscription(auctions.AuctionCancelled, "notify-auction-cancelled", pubsub.SubscriptionConfig[*auctions.AuctionCancelledEvent]{
+	Handler: notifyAuctionCancelled,
+})
+
+// notifyAuctionCancelled emails everyone who bid on a cancelled auction.
+func notifyAuctionCancelled(ctx context.Context, event *auctions.AuctionCancelledEvent) error {
+	return notifyAuctionUsers(ctx, event.AuctionID, event.ItemID, event.BidderIDs, "cancelled",
+		func(ctx context.Context, itemTitle string, to *recipient) (*email.EmailResponse, error) {
+			return email.SendAuctionCancelledNotice(ctx, &email.AuctionCancelledEmailRequest{
+				BidderEmail: to.Email,
+				BidderName:  to.Name,
+				AuctionID:   event.AuctionID.String(),
+				ItemTitle:   itemTitle,
+				Reason:      event.Reason,
+			})
+		})
+}
+
+var _ = pubsub.NewSubscription(bids.AuctionExtended, "notify-auction-extended", pubsub.SubscriptionConfig[*bids.AuctionExtendedEvent]{
+	Handler: notifyAuctionExtended,
+})
+
+// notifyAuctionExtended emails everyone following an auction its new end time
+// after a late bid extended it: watchers of the auction or its item, and
+// everyone who has bid, except the bidder whose bid caused it.
+func notifyAuctionExtended(ctx context.Context, event *bids.AuctionExtendedEvent) error {
+	rows, err := db.Query(ctx, `
+		SELECT user_id FROM (
+			SELECT user_id FROM watchlist WHERE auction_id = $1 OR item_id = $2
+			UNION
+			SELECT user_id FROM bids WHERE auction_id = $1 AND user_id IS NOT NULL AND voided_at IS NULL
+		) followers
+		WHERE user_id <> $3
+	`, event.AuctionID, event.ItemID, event.BidderID)
+	if err != nil {
+		return fmt.Errorf("load followers: %w", err)
+	}
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan follower: %w", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate followers: %w", err)
+	}
+
+	return notifyAuctionUsers(ctx, event.AuctionID, event.ItemID, userIDs, "extended",
+		func(ctx context.Context, itemTitle string, to *recipient) (*email.EmailResponse, error) {
+			return email.SendAuctionExtendedNotice(ctx, &email.AuctionExtendedEmailRequest{
+				WatcherEmail: to.Email,
+				WatcherName:  to.Name,
+				AuctionID:    event.AuctionID.String(),
+				ItemTitle:    itemTitle,
+				NewEndTime:   event.EndsAt.Format(time.RFC1123),
+				AuctionURL:   fmt.Sprintf("%s/auctions/%s", siteURL, event.AuctionID),
+			})
+		})
+}
+
+// recipient is a user to email about an auction.
+type recipient struct {
+	Email string
+	Name  string
+}
+
+// auctionNotice sends one auction email, about the item titled itemTitle.
+type auctionNotice func(ctx context.Context, itemTitle string, to *recipient) (*email.EmailResponse, error)
+
+// notifyAuctionUsers sends an auction notice, named by kind in logs, to each
+// of userIDs. A failure for one user is logged rather than retried, so a
+// redelivery doesn't email the others twice.
+func notifyAuctionUsers(ctx context.Context, auctionID, itemID uuid.UUID, userIDs []uuid.UUID, kind string, send auctionNotice) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	var itemTitle string
+	err := db.QueryRow(ctx, "SELECT title FROM items WHERE id = $1", itemID).Scan(&itemTitle)
+	if err != nil && !errors.Is(err, sqldb.ErrNoRows) {
+		return fmt.Errorf("load item: %w", err)
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT email, COALESCE(name, '') FROM users WHERE id = ANY($1)
+	`, userIDs)
+	if err != nil {
+		return fmt.Errorf("load recipients: %w", err)
+	}
+	var recipients []*recipient
+	for rows.Next() {
+		r := &recipient{}
+		if err := rows.Scan(&r.Email, &r.Name); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate recipients: %w", err)
+	}
+
+	for _, r := range recipients {
+		resp, err := send(ctx, itemTitle, r)
+		if err != nil {
+			rlog.Error("failed to send auction "+kind+" email", "auction_id", auctionID, "err", err)
+		} else if !resp.Success {
+			rlog.Error("auction "+kind+" email rejected", "auction_id", auctionID, "message", resp.Message)
+		}
+	}
+	return nil
+}
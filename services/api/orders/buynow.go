@@ -0,0 +1,200 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+)
+
+//encore:api public method=POST path=/v1/items/:id/buy-now
+func BuyNow(ctx context.Context, id string, req *BuyNowRequest) (*Order, error) {
+	// AI-CHAT: Fixed-price purchase at the item's buy-now price
+	// Stock is reserved in the same transaction that creates the order, so
+	// two buyers can never take the last unit. Taking the last unit ends any
+	// auction that hasn't received bids yet; once bidding has started the
+	// last unit is reserved for the auction winner.
+
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	if req.UserID == uuid.Nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("user_id is required").Err()
+	}
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("quantity must be positive").Err()
+	}
+	provider := req.PaymentProvider
+	if provider == "" {
+		provider = "stripe"
+	}
+	if provider != "stripe" && provider != "crypto_placeholder" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg(`payment_provider must be "stripe" or "crypto_placeholder"`).Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var (
+		status  string
+		price   *float64
+		onHand  int
+		inLotID *uuid.UUID
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT status, buy_now_price, quantity, lot_id
+		FROM items
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, itemID).Scan(&status, &price, &onHand, &inLotID)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	switch {
+	case price == nil:
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("item has no buy-now price").Err()
+	case inLotID != nil:
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("items in a lot can only be bought with the lot").Err()
+	case status != "listed" && status != "in_auction":
+		return nil, errs.B().Code(errs.FailedPrecondition).Msgf("item is %s and not for sale", status).Err()
+	case onHand < quantity:
+		return nil, errs.B().Code(errs.FailedPrecondition).Msgf("only %d left in stock", onHand).Err()
+	}
+
+	remaining := onHand - quantity
+	if remaining == 0 {
+		if err := cancelAuctionsForSoldOutItem(ctx, tx, itemID, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE items
+		SET quantity = $2, status = CASE WHEN $2 = 0 THEN 'sold' ELSE status END, updated_at = NOW()
+		WHERE id = $1
+	`, itemID, remaining)
+	if err != nil {
+		return nil, fmt.Errorf("reserve stock: %w", err)
+	}
+	if remaining == 0 {
+		// Selling a lot's listing sells everything grouped into the lot
+		_, err = tx.Exec(ctx, `
+			UPDATE items SET status = 'sold', updated_at = NOW()
+			WHERE lot_id = (SELECT id FROM lots WHERE item_id = $1) AND deleted_at IS NULL
+		`, itemID)
+		if err != nil {
+			return nil, fmt.Errorf("mark lot items sold: %w", err)
+		}
+	}
+
+	order := &Order{
+		ID:              uuid.New(),
+		UserID:          req.UserID,
+		ItemID:          itemID,
+		Quantity:        quantity,
+		Total:           buyNowTotal(*price, quantity),
+		PaymentProvider: provider,
+		Status:          string(OrderPending),
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO orders (id, user_id, item_id, quantity, total, payment_provider, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, order.ID, order.UserID, order.ItemID, order.Quantity, order.Total,
+		order.PaymentProvider, order.Status).Scan(&order.CreatedAt)
+	if err != nil {
+		if sqldb.ErrCode(err) == sqlerr.ForeignKeyViolation {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("user_id does not exist").Err()
+		}
+		return nil, fmt.Errorf("insert order: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit order: %w", err)
+	}
+
+	// TODO: Start payment checkout for the new order
+
+	return order, nil
+}
+
+// cancelAuctionsForSoldOutItem cancels any auction that has not finished for
+// an item whose last unit is being bought. It refuses the purchase instead if
+// an open auction already has bids, since those bidders were promised a fair
+// shot at the item.
+func cancelAuctionsForSoldOutItem(ctx context.Context, tx *sqldb.Tx, itemID, actorID uuid.UUID) error {
+	rows, err := tx.Query(ctx, `
+		SELECT a.id, a.status, (SELECT COUNT(*) FROM bids b WHERE b.auction_id = a.id)
+		FROM auctions a
+		WHERE a.item_id = $1 AND a.status IN ('draft', 'scheduled', 'open')
+		FOR UPDATE OF a
+	`, itemID)
+	if err != nil {
+		return fmt.Errorf("query running auctions: %w", err)
+	}
+	var auctionIDs []uuid.UUID
+	for rows.Next() {
+		var (
+			auctionID uuid.UUID
+			status    string
+			bidCount  int
+		)
+		if err := rows.Scan(&auctionID, &status, &bidCount); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan auction: %w", err)
+		}
+		if bidCount > 0 {
+			rows.Close()
+			return errs.B().Code(errs.FailedPrecondition).Msg("the last unit is up for auction and already has bids").Err()
+		}
+		auctionIDs = append(auctionIDs, auctionID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query running auctions: %w", err)
+	}
+
+	meta, err := json.Marshal(map[string]string{"reason": "sold out through buy-now"})
+	if err != nil {
+		return fmt.Errorf("encode audit meta: %w", err)
+	}
+	for _, auctionID := range auctionIDs {
+		if _, err := tx.Exec(ctx, "UPDATE auctions SET status = 'cancelled' WHERE id = $1", auctionID); err != nil {
+			return fmt.Errorf("cancel auction: %w", err)
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+			VALUES ($1, 'auction.cancelled', 'auction', $2, $3)
+		`, actorID, auctionID, meta)
+		if err != nil {
+			return fmt.Errorf("record auction cancellation: %w", err)
+		}
+	}
+	return nil
+}
+
+// buyNowTotal prices a purchase of quantity units, rounded to the cent.
+func buyNowTotal(price float64, quantity int) float64 {
+	return math.Round(price*float64(quantity)*100) / 100
+}
+
+type BuyNowRequest struct {
+	UserID          uuid.UUID `json:"user_id"`
+	Quantity        int       `json:"quantity,omitempty"`         // Defaults to 1
+	PaymentProvider string    `json:"payment_provider,omitempty"` // "stripe" (default) or "crypto_placeholder"
+}
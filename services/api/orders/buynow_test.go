@@ -0,0 +1,22 @@
+package orders
+
+import "testing"
+
+func TestBuyNowTotal(t *testing.T) {
+	testCases := []struct {
+		price    float64
+		quantity int
+		expected float64
+	}{
+		{25.00, 1, 25.00},
+		{19.99, 3, 59.97},
+		{0.10, 3, 0.30}, // Not 0.30000000000000004
+		{12.50, 40, 500.00},
+	}
+
+	for _, tc := range testCases {
+		if got := buyNowTotal(tc.price, tc.quantity); got != tc.expected {
+			t.Errorf("buyNowTotal(%v, %d) = %v, expected %v", tc.price, tc.quantity, got, tc.expected)
+		}
+	}
+}
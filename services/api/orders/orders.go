@@ -2,7 +2,40 @@
 
 package orders
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// Order records a purchase, either an auction win or a buy-now sale
+type Order struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	ItemID          uuid.UUID  `json:"item_id" db:"item_id"`
+	AuctionID       *uuid.UUID `json:"auction_id,omitempty" db:"auction_id"` // Nil for buy-now purchases
+	Quantity        int        `json:"quantity" db:"quantity"`
+	Total           float64    `json:"total" db:"total"`
+	PaymentProvider string     `json:"payment_provider" db:"payment_provider"`
+	Status          string     `json:"status" db:"status"`
+	PickupDate      *time.Time `json:"pickup_date,omitempty" db:"pickup_date"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OrderStatus defines order payment states
+type OrderStatus string
+
+const (
+	OrderPending  OrderStatus = "pending"
+	OrderPaid     OrderStatus = "paid"
+	OrderRefunded OrderStatus = "refunded"
+	OrderFailed   OrderStatus = "failed"
+	OrderVoided   OrderStatus = "voided" // Auction win not paid by the deadline
+)
+
+var db = sqldb.Named("seattle_reuse")
 
 //encore:api public method=POST path=/v1/checkout/stripe
 func CreateStripeCheckout(ctx context.Context, req *CheckoutRequest) (*CheckoutResponse, error) {
@@ -16,4 +49,4 @@ type CheckoutRequest struct {
 
 type CheckoutResponse struct {
 	CheckoutURL string `json:"checkout_url"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,65 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"github.com/google/uuid"
+)
+
+// PickupTimeZone is the warehouse's local time zone. It decides which
+// calendar day counts as today, both for booking pickups and for the
+// warehouse's daily pick lists.
+const PickupTimeZone = "America/Los_Angeles"
+
+//encore:api public method=PUT path=/v1/orders/:id/pickup
+func SchedulePickup(ctx context.Context, id string, req *SchedulePickupRequest) (*SchedulePickupResponse, error) {
+	// AI-CHAT: Books the day a buyer collects a paid order
+	// Orders booked for a day show up on that day's warehouse pick list.
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid order id").Err()
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("date must be YYYY-MM-DD").Err()
+	}
+
+	var (
+		status string
+		past   bool
+	)
+	err = db.QueryRow(ctx, `
+		SELECT status, $2::date < (NOW() AT TIME ZONE $3)::date
+		FROM orders WHERE id = $1
+	`, orderID, req.Date, PickupTimeZone).Scan(&status, &past)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("order %s not found", orderID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load order: %w", err)
+	}
+	if status != string(OrderPaid) {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("only paid orders can be scheduled for pickup").Err()
+	}
+	if past {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("pickup date is in the past").Err()
+	}
+
+	if _, err := db.Exec(ctx, "UPDATE orders SET pickup_date = $2 WHERE id = $1", orderID, req.Date); err != nil {
+		return nil, fmt.Errorf("schedule pickup: %w", err)
+	}
+	return &SchedulePickupResponse{OrderID: orderID, PickupDate: date}, nil
+}
+
+type SchedulePickupRequest struct {
+	Date string `json:"date"` // YYYY-MM-DD
+}
+
+type SchedulePickupResponse struct {
+	OrderID    uuid.UUID `json:"order_id"`
+	PickupDate time.Time `json:"pickup_date"`
+}
@@ -0,0 +1,248 @@
+// Package screening checks listings and donations against the prohibited
+// items policy (POLICY_PROHIBITED_ITEMS.md) for the catalog and donations
+// services.
+package screening
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Action is what happens to a listing that matches a rule
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionReview Action = "review" // Held back until staff approve it
+	ActionBlock  Action = "block"  // Rejected outright
+)
+
+// Rule is a set of terms from one section of the policy. Terms and
+// exceptions match whole words, case-insensitively; an exception such as
+// "glue gun" stops the words it covers from matching "gun".
+type Rule struct {
+	ID         string   `json:"id"`
+	Policy     string   `json:"policy"` // Policy section the rule enforces
+	Action     Action   `json:"action"`
+	Terms      []string `json:"terms"`
+	Exceptions []string `json:"exceptions,omitempty"`
+	Categories []string `json:"categories,omitempty"` // Only applies to these category slugs when set
+}
+
+// Listing is the text screened for a new item or donation.
+type Listing struct {
+	Title       string
+	Description string
+	Categories  []string // Category slugs and names, including parents
+}
+
+// Match records which rule matched where.
+type Match struct {
+	RuleID string `json:"rule_id"`
+	Policy string `json:"policy"`
+	Action Action `json:"action"`
+	Term   string `json:"term"`
+	Field  string `json:"field"` // "title", "description" or "category"
+}
+
+// Decision is the outcome of screening a listing: the strictest action of
+// all matched rules.
+type Decision struct {
+	Action  Action   `json:"action"`
+	Matches []*Match `json:"matches,omitempty"`
+}
+
+// Deciding returns the first match whose action set the decision, or nil
+// when nothing matched.
+func (d *Decision) Deciding() *Match {
+	for _, m := range d.Matches {
+		if m.Action == d.Action {
+			return m
+		}
+	}
+	return nil
+}
+
+// Reason summarises the decision for error messages and reviewers.
+func (d *Decision) Reason() string {
+	m := d.Deciding()
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %q matches %s (%s)", m.Field, m.Term, m.Policy, m.RuleID)
+}
+
+// Engine screens listings against a rule set.
+type Engine struct {
+	rules []*compiledRule
+}
+
+type compiledRule struct {
+	*Rule
+	terms      [][]string // Normalized forms of each term, parallel to Terms
+	exceptions []string
+}
+
+//go:embed rules.json
+var defaultRules []byte
+
+var defaultEngine = mustLoadDefault()
+
+// Default returns the engine built from the bundled rules.json. Edit that
+// file to change what is prohibited or restricted.
+func Default() *Engine {
+	return defaultEngine
+}
+
+func mustLoadDefault() *Engine {
+	var rules []*Rule
+	if err := json.Unmarshal(defaultRules, &rules); err != nil {
+		panic(fmt.Sprintf("screening: decode rules.json: %v", err))
+	}
+	engine, err := New(rules)
+	if err != nil {
+		panic(fmt.Sprintf("screening: %v", err))
+	}
+	return engine
+}
+
+// New builds an engine from rules, rejecting malformed ones.
+func New(rules []*Rule) (*Engine, error) {
+	engine := &Engine{}
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		switch {
+		case r.ID == "":
+			return nil, fmt.Errorf("rule without id")
+		case seen[r.ID]:
+			return nil, fmt.Errorf("rule %s is defined twice", r.ID)
+		case r.Action != ActionReview && r.Action != ActionBlock:
+			return nil, fmt.Errorf("rule %s: action must be review or block", r.ID)
+		case len(r.Terms) == 0:
+			return nil, fmt.Errorf("rule %s has no terms", r.ID)
+		}
+		seen[r.ID] = true
+
+		c := &compiledRule{Rule: r}
+		for _, t := range r.Terms {
+			c.terms = append(c.terms, wordForms(t))
+		}
+		for _, e := range r.Exceptions {
+			c.exceptions = append(c.exceptions, wordForms(e)...)
+		}
+		engine.rules = append(engine.rules, c)
+	}
+	return engine, nil
+}
+
+// Screen checks a listing against every rule.
+func (e *Engine) Screen(l *Listing) *Decision {
+	fields := []struct {
+		name string
+		text string
+	}{
+		{"title", normalize(l.Title)},
+		{"description", normalize(l.Description)},
+		{"category", normalize(strings.Join(l.Categories, " "))},
+	}
+	categories := make(map[string]bool, len(l.Categories))
+	for _, c := range l.Categories {
+		categories[strings.ToLower(c)] = true
+	}
+
+	decision := &Decision{Action: ActionAllow}
+	for _, rule := range e.rules {
+		if !rule.appliesTo(categories) {
+			continue
+		}
+		for _, f := range fields {
+			if term, ok := rule.match(f.text); ok {
+				decision.Matches = append(decision.Matches, &Match{
+					RuleID: rule.ID,
+					Policy: rule.Policy,
+					Action: rule.Action,
+					Term:   term,
+					Field:  f.name,
+				})
+				decision.Action = stricter(decision.Action, rule.Action)
+			}
+		}
+	}
+	return decision
+}
+
+func (r *compiledRule) appliesTo(categories map[string]bool) bool {
+	if len(r.Categories) == 0 {
+		return true
+	}
+	for _, c := range r.Categories {
+		if categories[c] {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the first term found in text once the rule's exceptions
+// have been cut out of it.
+func (r *compiledRule) match(text string) (string, bool) {
+	if text == " " {
+		return "", false
+	}
+	for _, exception := range r.exceptions {
+		text = strings.ReplaceAll(text, exception, " ")
+	}
+	for i, forms := range r.terms {
+		for _, form := range forms {
+			if strings.Contains(text, form) {
+				return r.Terms[i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// wordForms returns the normalized phrase and its simple plurals, so
+// "firearm" also matches "firearms" and "glue gun" covers "glue guns".
+func wordForms(phrase string) []string {
+	n := normalize(phrase)
+	stem := strings.TrimSuffix(n, " ")
+	return []string{n, stem + "s ", stem + "es "}
+}
+
+// normalize lower-cases s, strips accents and turns everything but letters
+// and digits into single spaces, padded so " term " matches whole words.
+func normalize(s string) string {
+	var b strings.Builder
+	b.WriteByte(' ')
+	space := true
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent left over from decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case !space:
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	if !space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+func stricter(a, b Action) Action {
+	rank := map[Action]int{ActionAllow: 0, ActionReview: 1, ActionBlock: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,68 @@
+package screening
+
+import "testing"
+
+func TestDefaultRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		listing  *Listing
+		expected Action
+		rule     string
+	}{
+		{"office chair", &Listing{Title: "Herman Miller Aeron Chair", Description: "Size B, fully loaded"}, ActionAllow, ""},
+		{"firearm", &Listing{Title: "Antique hunting rifle"}, ActionBlock, "weapons.firearms"},
+		{"plural", &Listing{Title: "Box of fireworks"}, ActionBlock, "weapons.explosives"},
+		{"case and punctuation", &Listing{Description: "Comes with PEPPER-SPRAY keychain"}, ActionBlock, "weapons.self_defense"},
+		{"tool exception", &Listing{Title: "Cordless glue guns (2)"}, ActionAllow, ""},
+		{"exception does not hide other terms", &Listing{Title: "Hot glue gun and a pistol"}, ActionBlock, "weapons.firearms"},
+		{"whole words only", &Listing{Title: "Begun project: shotglass display shelf"}, ActionAllow, ""},
+		{"restricted", &Listing{Title: "Decorative display sword"}, ActionReview, "weapons.blades"},
+		{"wine rack allowed", &Listing{Title: "Wine rack, 12 bottles"}, ActionAllow, ""},
+		{"block beats review", &Listing{Title: "Wine and vape bundle"}, ActionBlock, "tobacco_vaping"},
+		{"accents", &Listing{Description: "Genuine ívory inlay"}, ActionBlock, "wildlife"},
+		{"category scoped", &Listing{Title: "iPad, iCloud locked", Categories: []string{"electronics", "Electronics"}}, ActionReview, "electronics.locked"},
+		{"category scoped elsewhere", &Listing{Title: "Desk drawer, activation lock key"}, ActionAllow, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Default().Screen(tc.listing)
+			if d.Action != tc.expected {
+				t.Fatalf("Screen() = %s (%+v), expected %s", d.Action, d.Matches, tc.expected)
+			}
+			if tc.rule == "" {
+				return
+			}
+			for _, m := range d.Matches {
+				if m.RuleID == tc.rule {
+					return
+				}
+			}
+			t.Errorf("Expected a match for rule %s, got %+v", tc.rule, d.Matches)
+		})
+	}
+}
+
+func TestDecisionReason(t *testing.T) {
+	d := Default().Screen(&Listing{Title: "Wine fridge", Description: "Includes a taser"})
+	if got, want := d.Reason(), `description "taser" matches Weapons and Dangerous Items (weapons.self_defense)`; got != want {
+		t.Errorf("Reason() = %q, expected %q", got, want)
+	}
+	if got := Default().Screen(&Listing{Title: "Desk"}).Reason(); got != "" {
+		t.Errorf("Expected no reason for an allowed listing, got %q", got)
+	}
+}
+
+func TestNewRejectsBadRules(t *testing.T) {
+	bad := [][]*Rule{
+		{{Action: ActionBlock, Terms: []string{"x"}}},
+		{{ID: "a", Action: ActionAllow, Terms: []string{"x"}}},
+		{{ID: "a", Action: ActionBlock}},
+		{{ID: "a", Action: ActionBlock, Terms: []string{"x"}}, {ID: "a", Action: ActionReview, Terms: []string{"y"}}},
+	}
+	for i, rules := range bad {
+		if _, err := New(rules); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
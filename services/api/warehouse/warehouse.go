@@ -0,0 +1,487 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+
+	"seattlereuse.exchange/api/orders"
+)
+
+// Warehouse is a physical site where donated items are stored
+type Warehouse struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"` // Short code printed on bin labels, e.g. "SODO"
+	Name      string    `json:"name" db:"name"`
+	Address   string    `json:"address,omitempty" db:"address"`
+	Zones     []*Zone   `json:"zones"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Zone is an area of a warehouse, such as a bay or aisle
+type Zone struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	WarehouseID uuid.UUID `json:"warehouse_id" db:"warehouse_id"`
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name,omitempty" db:"name"`
+	Bins        []*Bin    `json:"bins"`
+}
+
+// Bin is a single shelf or floor spot that items are put away in
+type Bin struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	ZoneID uuid.UUID `json:"zone_id" db:"zone_id"`
+	Code   string    `json:"code" db:"code"`
+	Label  string    `json:"label"` // Full location, e.g. "SODO/B/03-2"
+}
+
+// ItemMove is one entry in an item's location history
+type ItemMove struct {
+	ItemID    uuid.UUID  `json:"item_id"`
+	FromBinID *uuid.UUID `json:"from_bin_id,omitempty"`
+	FromLabel string     `json:"from_label,omitempty"`
+	ToBinID   *uuid.UUID `json:"to_bin_id,omitempty"`
+	ToLabel   string     `json:"to_label,omitempty"`
+	MovedBy   *uuid.UUID `json:"moved_by,omitempty"`
+	MovedAt   time.Time  `json:"moved_at"`
+}
+
+// PickListEntry tells a picker where to find one item for an order
+type PickListEntry struct {
+	OrderID    uuid.UUID  `json:"order_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	ItemID     uuid.UUID  `json:"item_id"`
+	ItemTitle  string     `json:"item_title"`
+	Quantity   int        `json:"quantity"`
+	BinID      *uuid.UUID `json:"bin_id,omitempty"`
+	BinLabel   string     `json:"bin_label,omitempty"` // Empty when the item hasn't been put away
+	PickupDate time.Time  `json:"pickup_date"`
+}
+
+var db = sqldb.Named("seattle_reuse")
+
+//encore:api public method=GET path=/v1/warehouses
+func GetWarehouses(ctx context.Context) (*GetWarehousesResponse, error) {
+	// AI-CHAT: Every warehouse with its zones and bins, for put-away screens
+	rows, err := db.Query(ctx, `
+		SELECT w.id, w.code, w.name, COALESCE(w.address, ''), w.created_at,
+			z.id, z.code, COALESCE(z.name, ''), b.id, b.code
+		FROM warehouses w
+		LEFT JOIN warehouse_zones z ON z.warehouse_id = w.id
+		LEFT JOIN warehouse_bins b ON b.zone_id = z.id
+		ORDER BY w.code, z.code, b.code
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query warehouses: %w", err)
+	}
+	defer rows.Close()
+
+	warehouses := []*Warehouse{}
+	var (
+		w *Warehouse
+		z *Zone
+	)
+	for rows.Next() {
+		var (
+			row      Warehouse
+			zoneID   *uuid.UUID
+			zoneCode *string
+			zoneName *string
+			binID    *uuid.UUID
+			binCode  *string
+		)
+		err := rows.Scan(&row.ID, &row.Code, &row.Name, &row.Address, &row.CreatedAt,
+			&zoneID, &zoneCode, &zoneName, &binID, &binCode)
+		if err != nil {
+			return nil, fmt.Errorf("scan warehouse: %w", err)
+		}
+		if w == nil || w.ID != row.ID {
+			w = &row
+			w.Zones = []*Zone{}
+			warehouses = append(warehouses, w)
+			z = nil
+		}
+		if zoneID == nil {
+			continue
+		}
+		if z == nil || z.ID != *zoneID {
+			z = &Zone{ID: *zoneID, WarehouseID: w.ID, Code: *zoneCode, Name: *zoneName, Bins: []*Bin{}}
+			w.Zones = append(w.Zones, z)
+		}
+		if binID != nil {
+			z.Bins = append(z.Bins, &Bin{ID: *binID, ZoneID: z.ID, Code: *binCode, Label: binLabel(w.Code, z.Code, *binCode)})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate warehouses: %w", err)
+	}
+	return &GetWarehousesResponse{Warehouses: warehouses}, nil
+}
+
+//encore:api public method=POST path=/v1/warehouses
+func CreateWarehouse(ctx context.Context, req *CreateWarehouseRequest) (*Warehouse, error) {
+	// AI-CHAT: Admin endpoint for adding a warehouse site
+	code, err := normalizeCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("name is required").Err()
+	}
+
+	w := &Warehouse{
+		ID:      uuid.New(),
+		Code:    code,
+		Name:    strings.TrimSpace(req.Name),
+		Address: req.Address,
+		Zones:   []*Zone{},
+	}
+	err = db.QueryRow(ctx, `
+		INSERT INTO warehouses (id, code, name, address)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, w.ID, w.Code, w.Name, w.Address).Scan(&w.CreatedAt)
+	if err != nil {
+		return nil, locationWriteError(err, "warehouse")
+	}
+	return w, nil
+}
+
+//encore:api public method=POST path=/v1/warehouses/:id/zones
+func CreateZone(ctx context.Context, id string, req *CreateZoneRequest) (*Zone, error) {
+	// AI-CHAT: Adds a zone (bay or aisle) to a warehouse
+	warehouseID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid warehouse id").Err()
+	}
+	code, err := normalizeCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	z := &Zone{
+		ID:          uuid.New(),
+		WarehouseID: warehouseID,
+		Code:        code,
+		Name:        req.Name,
+		Bins:        []*Bin{},
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO warehouse_zones (id, warehouse_id, code, name)
+		VALUES ($1, $2, $3, $4)
+	`, z.ID, z.WarehouseID, z.Code, z.Name)
+	if err != nil {
+		return nil, locationWriteError(err, "zone")
+	}
+	return z, nil
+}
+
+//encore:api public method=POST path=/v1/warehouse-zones/:id/bins
+func CreateBin(ctx context.Context, id string, req *CreateBinRequest) (*Bin, error) {
+	// AI-CHAT: Adds a bin (shelf or floor spot) to a zone
+	zoneID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid zone id").Err()
+	}
+	code, err := normalizeCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	var warehouseCode, zoneCode string
+	err = db.QueryRow(ctx, `
+		SELECT w.code, z.code
+		FROM warehouse_zones z JOIN warehouses w ON w.id = z.warehouse_id
+		WHERE z.id = $1
+	`, zoneID).Scan(&warehouseCode, &zoneCode)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("zone %s not found", zoneID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load zone: %w", err)
+	}
+
+	b := &Bin{
+		ID:     uuid.New(),
+		ZoneID: zoneID,
+		Code:   code,
+		Label:  binLabel(warehouseCode, zoneCode, code),
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO warehouse_bins (id, zone_id, code) VALUES ($1, $2, $3)
+	`, b.ID, b.ZoneID, b.Code)
+	if err != nil {
+		return nil, locationWriteError(err, "bin")
+	}
+	return b, nil
+}
+
+//encore:api public method=PUT path=/v1/items/:id/bin
+func AssignItemBin(ctx context.Context, id string, req *AssignItemBinRequest) (*ItemMove, error) {
+	// AI-CHAT: Puts an item away in a bin, or takes it off the shelves
+	// Every move is recorded in the audit log so a missing item can be
+	// traced back through the bins it has been in.
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	move := &ItemMove{ItemID: itemID, ToBinID: req.BinID, MovedBy: req.MovedBy}
+	err = tx.QueryRow(ctx, `
+		SELECT bin_id FROM items WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, itemID).Scan(&move.FromBinID)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return nil, errs.B().Code(errs.NotFound).Msgf("item %s not found", itemID).Err()
+	} else if err != nil {
+		return nil, fmt.Errorf("load item: %w", err)
+	}
+	if sameBin(move.FromBinID, move.ToBinID) {
+		return nil, errs.B().Code(errs.FailedPrecondition).Msg("item is already in that bin").Err()
+	}
+	if move.FromLabel, err = loadBinLabel(ctx, tx, move.FromBinID); err != nil {
+		return nil, err
+	}
+	if move.ToLabel, err = loadBinLabel(ctx, tx, move.ToBinID); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE items SET bin_id = $2, updated_at = NOW() WHERE id = $1", itemID, move.ToBinID)
+	if err != nil {
+		return nil, fmt.Errorf("update item bin: %w", err)
+	}
+	meta, err := json.Marshal(moveMeta{
+		FromBinID: move.FromBinID,
+		FromLabel: move.FromLabel,
+		ToBinID:   move.ToBinID,
+		ToLabel:   move.ToLabel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode move: %w", err)
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity, entity_id, meta)
+		VALUES ($1, 'item.moved', 'item', $2, $3)
+		RETURNING created_at
+	`, move.MovedBy, itemID, meta).Scan(&move.MovedAt)
+	if err != nil {
+		return nil, fmt.Errorf("record item move: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit item move: %w", err)
+	}
+	return move, nil
+}
+
+//encore:api public method=GET path=/v1/items/:id/moves
+func GetItemMoves(ctx context.Context, id string) (*GetItemMovesResponse, error) {
+	// AI-CHAT: An item's location history, newest first
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("invalid item id").Err()
+	}
+	rows, err := db.Query(ctx, `
+		SELECT actor_id, meta, created_at
+		FROM audit_log
+		WHERE entity = 'item' AND entity_id = $1 AND action = 'item.moved'
+		ORDER BY created_at DESC
+	`, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("query item moves: %w", err)
+	}
+	defer rows.Close()
+
+	moves := []*ItemMove{}
+	for rows.Next() {
+		var (
+			raw  []byte
+			meta moveMeta
+		)
+		move := &ItemMove{ItemID: itemID}
+		if err := rows.Scan(&move.MovedBy, &raw, &move.MovedAt); err != nil {
+			return nil, fmt.Errorf("scan item move: %w", err)
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("decode item move: %w", err)
+		}
+		move.FromBinID, move.FromLabel = meta.FromBinID, meta.FromLabel
+		move.ToBinID, move.ToLabel = meta.ToBinID, meta.ToLabel
+		moves = append(moves, move)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate item moves: %w", err)
+	}
+	return &GetItemMovesResponse{Moves: moves}, nil
+}
+
+//encore:api public method=GET path=/v1/pick-list
+func GetPickList(ctx context.Context, req *GetPickListRequest) (*GetPickListResponse, error) {
+	// AI-CHAT: Where to find everything buyers are collecting today
+	// Paid orders scheduled for pickup on the day are listed in warehouse,
+	// zone and bin order so pickers can walk the floor once. Items that were
+	// never put away are listed last.
+	date := req.Date
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, errs.B().Code(errs.InvalidArgument).Msg("date must be YYYY-MM-DD").Err()
+		}
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT o.id, o.user_id, o.item_id, i.title, o.quantity, i.bin_id,
+			w.code, z.code, b.code, o.pickup_date
+		FROM orders o
+		JOIN items i ON i.id = o.item_id
+		LEFT JOIN warehouse_bins b ON b.id = i.bin_id
+		LEFT JOIN warehouse_zones z ON z.id = b.zone_id
+		LEFT JOIN warehouses w ON w.id = z.warehouse_id
+		WHERE o.status = 'paid'
+			AND o.pickup_date = COALESCE(NULLIF($1, '')::date, (NOW() AT TIME ZONE $2)::date)
+		ORDER BY w.code NULLS LAST, z.code, b.code, o.created_at
+	`, date, orders.PickupTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("query pick list: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*PickListEntry{}
+	for rows.Next() {
+		var (
+			e                                PickListEntry
+			warehouseCode, zoneCode, binCode *string
+		)
+		err := rows.Scan(&e.OrderID, &e.UserID, &e.ItemID, &e.ItemTitle, &e.Quantity, &e.BinID,
+			&warehouseCode, &zoneCode, &binCode, &e.PickupDate)
+		if err != nil {
+			return nil, fmt.Errorf("scan pick list entry: %w", err)
+		}
+		if binCode != nil {
+			e.BinLabel = binLabel(*warehouseCode, *zoneCode, *binCode)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate pick list: %w", err)
+	}
+	return &GetPickListResponse{Entries: entries}, nil
+}
+
+// moveMeta is the audit_log meta recorded for an item move.
+type moveMeta struct {
+	FromBinID *uuid.UUID `json:"from_bin_id,omitempty"`
+	FromLabel string     `json:"from_label,omitempty"`
+	ToBinID   *uuid.UUID `json:"to_bin_id,omitempty"`
+	ToLabel   string     `json:"to_label,omitempty"`
+}
+
+// loadBinLabel returns the printed label of a bin, or "" for no bin.
+func loadBinLabel(ctx context.Context, tx *sqldb.Tx, binID *uuid.UUID) (string, error) {
+	if binID == nil {
+		return "", nil
+	}
+	var warehouseCode, zoneCode, code string
+	err := tx.QueryRow(ctx, `
+		SELECT w.code, z.code, b.code
+		FROM warehouse_bins b
+		JOIN warehouse_zones z ON z.id = b.zone_id
+		JOIN warehouses w ON w.id = z.warehouse_id
+		WHERE b.id = $1
+	`, *binID).Scan(&warehouseCode, &zoneCode, &code)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return "", errs.B().Code(errs.NotFound).Msgf("bin %s not found", *binID).Err()
+	} else if err != nil {
+		return "", fmt.Errorf("load bin: %w", err)
+	}
+	return binLabel(warehouseCode, zoneCode, code), nil
+}
+
+// binLabel formats a bin's full location the way it's printed on shelf
+// labels: warehouse, zone and bin codes separated by slashes.
+func binLabel(warehouseCode, zoneCode, code string) string {
+	return warehouseCode + "/" + zoneCode + "/" + code
+}
+
+// normalizeCode upper-cases a location code and checks it only contains
+// characters that are safe on printed labels.
+func normalizeCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", errs.B().Code(errs.InvalidArgument).Msg("code is required").Err()
+	}
+	if len(code) > 16 {
+		return "", errs.B().Code(errs.InvalidArgument).Msg("code must be at most 16 characters").Err()
+	}
+	for _, r := range code {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return "", errs.B().Code(errs.InvalidArgument).Msg("code may only contain letters, digits and hyphens").Err()
+		}
+	}
+	return code, nil
+}
+
+func sameBin(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func locationWriteError(err error, kind string) error {
+	switch sqldb.ErrCode(err) {
+	case sqlerr.UniqueViolation:
+		return errs.B().Code(errs.AlreadyExists).Msgf("a %s with that code already exists", kind).Err()
+	case sqlerr.ForeignKeyViolation:
+		return errs.B().Code(errs.NotFound).Msgf("parent of %s not found", kind).Err()
+	}
+	return fmt.Errorf("insert %s: %w", kind, err)
+}
+
+// Request/Response types
+
+type GetWarehousesResponse struct {
+	Warehouses []*Warehouse `json:"warehouses"`
+}
+
+type CreateWarehouseRequest struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+}
+
+type CreateZoneRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
+type CreateBinRequest struct {
+	Code string `json:"code"`
+}
+
+type AssignItemBinRequest struct {
+	BinID   *uuid.UUID `json:"bin_id"` // Null takes the item off the shelves
+	MovedBy *uuid.UUID `json:"moved_by,omitempty"`
+}
+
+type GetItemMovesResponse struct {
+	Moves []*ItemMove `json:"moves"`
+}
+
+type GetPickListRequest struct {
+	Date string `query:"date"` // YYYY-MM-DD, defaults to today in Seattle
+}
+
+type GetPickListResponse struct {
+	Entries []*PickListEntry `json:"entries"`
+}
@@ -0,0 +1,56 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"sodo", "SODO", true},
+		{" b ", "B", true},
+		{"03-2", "03-2", true},
+		{"", "", false},
+		{"A/3", "", false},
+		{"bay 3", "", false},
+		{"ABCDEFGHIJKLMNOPQ", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := normalizeCode(tc.input)
+			if (err == nil) != tc.valid {
+				t.Fatalf("normalizeCode(%q) error = %v, expected valid = %v", tc.input, err, tc.valid)
+			}
+			if got != tc.expected {
+				t.Errorf("normalizeCode(%q) = %q, expected %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestBinLabel(t *testing.T) {
+	if got := binLabel("SODO", "B", "03-2"); got != "SODO/B/03-2" {
+		t.Errorf("binLabel() = %q, expected SODO/B/03-2", got)
+	}
+}
+
+func TestSameBin(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	aCopy := a
+
+	if !sameBin(nil, nil) {
+		t.Error("Expected no bin to equal no bin")
+	}
+	if !sameBin(&a, &aCopy) {
+		t.Error("Expected equal ids to be the same bin")
+	}
+	if sameBin(&a, &b) || sameBin(&a, nil) || sameBin(nil, &b) {
+		t.Error("Expected different bins to differ")
+	}
+}
@@ -0,0 +1,221 @@
+// Package watchlist lets users follow items and auctions they are interested
+// in. Watcher counts are kept on items and auctions so staff can gauge demand
+// before setting reserve prices.
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/storage/sqldb"
+	"encore.dev/storage/sqldb/sqlerr"
+	"github.com/google/uuid"
+)
+
+// Entry is one item or auction a user follows. Exactly one of ItemID and
+// AuctionID is set.
+type Entry struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	ItemID    *uuid.UUID `json:"item_id,omitempty"`
+	AuctionID *uuid.UUID `json:"auction_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Title     string     `json:"title"`
+	Slug      string     `json:"slug"`
+	Status    string     `json:"status"`            // Item status, or auction status for auctions
+	EndsAt    *time.Time `json:"ends_at,omitempty"` // Auctions only
+}
+
+var db = sqldb.Named("seattle_reuse")
+
+// entryColumns lists the columns scanEntry expects. Queries must alias
+// watchlist as "w" and left join items as "i" and auctions as "a".
+const entryColumns = `w.id, w.user_id, w.item_id, w.auction_id, w.created_at, i.title, i.slug,
+	COALESCE(a.status, i.status), a.ends_at`
+
+const entryJoins = `
+	LEFT JOIN auctions a ON a.id = w.auction_id
+	JOIN items i ON i.id = COALESCE(w.item_id, a.item_id)`
+
+//encore:api public method=POST path=/v1/watchlist
+func Watch(ctx context.Context, req *WatchRequest) (*Entry, error) {
+	// AI-CHAT: Follow an item or an auction
+	// Watching something already on the list returns the existing entry.
+	if _, _, ok := watchTarget(req.ItemID, req.AuctionID); !ok {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("exactly one of item_id and auction_id is required").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO watchlist (user_id, item_id, auction_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING id
+	`, req.UserID, req.ItemID, req.AuctionID).Scan(&id)
+	switch {
+	case errors.Is(err, sqldb.ErrNoRows):
+		// Already watching
+		err = tx.QueryRow(ctx, `
+			SELECT id FROM watchlist
+			WHERE user_id = $1 AND (item_id = $2 OR auction_id = $3)
+		`, req.UserID, req.ItemID, req.AuctionID).Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("load watchlist entry: %w", err)
+		}
+	case sqldb.ErrCode(err) == sqlerr.ForeignKeyViolation:
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("user, item or auction does not exist").Err()
+	case err != nil:
+		return nil, fmt.Errorf("insert watchlist entry: %w", err)
+	default:
+		if err := adjustWatcherCount(ctx, tx, req.ItemID, req.AuctionID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := scanEntry(tx.QueryRow(ctx, "SELECT "+entryColumns+" FROM watchlist w"+entryJoins+" WHERE w.id = $1", id))
+	if err != nil {
+		return nil, fmt.Errorf("load watchlist entry: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit watchlist entry: %w", err)
+	}
+	return entry, nil
+}
+
+//encore:api public method=DELETE path=/v1/watchlist/:id
+func Unwatch(ctx context.Context, id string, req *UnwatchRequest) error {
+	// AI-CHAT: Stop following an item or auction
+	// Only the user who owns the entry can remove it.
+	entryID, err := uuid.Parse(id)
+	if err != nil {
+		return errs.B().Code(errs.InvalidArgument).Msg("invalid watchlist entry id").Err()
+	}
+	if req.UserID == uuid.Nil {
+		return errs.B().Code(errs.InvalidArgument).Msg("user_id is required").Err()
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var itemID, auctionID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		DELETE FROM watchlist WHERE id = $1 AND user_id = $2
+		RETURNING item_id, auction_id
+	`, entryID, req.UserID).Scan(&itemID, &auctionID)
+	if errors.Is(err, sqldb.ErrNoRows) {
+		return errs.B().Code(errs.NotFound).Msgf("watchlist entry %s not found", entryID).Err()
+	} else if err != nil {
+		return fmt.Errorf("delete watchlist entry: %w", err)
+	}
+	if err := adjustWatcherCount(ctx, tx, itemID, auctionID, -1); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit watchlist removal: %w", err)
+	}
+	return nil
+}
+
+//encore:api public method=GET path=/v1/watchlist
+func GetWatchlist(ctx context.Context, req *GetWatchlistRequest) (*GetWatchlistResponse, error) {
+	// AI-CHAT: A user's watched items and auctions, auctions ending soonest
+	// first, then items most recently watched
+	if req.UserID == uuid.Nil {
+		return nil, errs.B().Code(errs.InvalidArgument).Msg("user_id is required").Err()
+	}
+
+	rows, err := db.Query(ctx, "SELECT "+entryColumns+" FROM watchlist w"+entryJoins+`
+		WHERE w.user_id = $1 AND i.deleted_at IS NULL
+		ORDER BY a.ends_at ASC NULLS LAST, w.created_at DESC
+	`, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("query watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &GetWatchlistResponse{Entries: []*Entry{}}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan watchlist entry: %w", err)
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate watchlist: %w", err)
+	}
+	return resp, nil
+}
+
+// watchTarget returns the table and id of what an entry watches. ok is false
+// unless exactly one of itemID and auctionID is set.
+func watchTarget(itemID, auctionID *uuid.UUID) (table string, id *uuid.UUID, ok bool) {
+	switch {
+	case itemID != nil && auctionID == nil:
+		return "items", itemID, true
+	case auctionID != nil && itemID == nil:
+		return "auctions", auctionID, true
+	}
+	return "", nil, false
+}
+
+// adjustWatcherCount moves the watcher count of the watched item or auction
+// by delta.
+func adjustWatcherCount(ctx context.Context, tx *sqldb.Tx, itemID, auctionID *uuid.UUID, delta int) error {
+	table, id, ok := watchTarget(itemID, auctionID)
+	if !ok {
+		return fmt.Errorf("watchlist entry must watch exactly one item or auction")
+	}
+	_, err := tx.Exec(ctx, `
+		UPDATE `+table+` SET watcher_count = GREATEST(watcher_count + $2, 0) WHERE id = $1
+	`, id, delta)
+	if err != nil {
+		return fmt.Errorf("update %s watcher count: %w", table, err)
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both *sqldb.Row and *sqldb.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEntry(row rowScanner) (*Entry, error) {
+	var e Entry
+	err := row.Scan(&e.ID, &e.UserID, &e.ItemID, &e.AuctionID, &e.CreatedAt, &e.Title, &e.Slug,
+		&e.Status, &e.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+type WatchRequest struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	ItemID    *uuid.UUID `json:"item_id,omitempty"`
+	AuctionID *uuid.UUID `json:"auction_id,omitempty"`
+}
+
+type UnwatchRequest struct {
+	UserID uuid.UUID `query:"user_id"` // Owner of the entry
+}
+
+type GetWatchlistRequest struct {
+	UserID uuid.UUID `query:"user_id"`
+}
+
+type GetWatchlistResponse struct {
+	Entries []*Entry `json:"entries"`
+}
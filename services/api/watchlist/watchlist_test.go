@@ -0,0 +1,34 @@
+package watchlist
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWatchTarget(t *testing.T) {
+	itemID, auctionID := uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name      string
+		itemID    *uuid.UUID
+		auctionID *uuid.UUID
+		table     string
+		id        *uuid.UUID
+		ok        bool
+	}{
+		{"item", &itemID, nil, "items", &itemID, true},
+		{"auction", nil, &auctionID, "auctions", &auctionID, true},
+		{"neither", nil, nil, "", nil, false},
+		{"both", &itemID, &auctionID, "", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			table, id, ok := watchTarget(tc.itemID, tc.auctionID)
+			if table != tc.table || id != tc.id || ok != tc.ok {
+				t.Errorf("watchTarget() = %q, %v, %v, expected %q, %v, %v", table, id, ok, tc.table, tc.id, tc.ok)
+			}
+		})
+	}
+}